@@ -17,12 +17,19 @@
 package cast
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/base64"
 	"encoding/json"
-	"log"
+	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 )
 
@@ -31,6 +38,24 @@ var (
 	JSON = &jsonEncoding{}
 )
 
+// Converter converts src to dest, which must be a non-nil pointer. JSON is
+// a Converter, and WithConverter makes To use one for structs and maps.
+type Converter interface {
+	Convert(src, dest any) error
+}
+
+// ConverterFunc adapts a func, like one that marshals src and unmarshals
+// it into dest with a third-party json library, or one that calls
+// FAST.Convert with some options, to a Converter.
+type ConverterFunc func(src, dest any) error
+
+// Convert calls f(src, dest).
+func (f ConverterFunc) Convert(src, dest any) error {
+	return f(src, dest)
+}
+
+var _ Converter = JSON
+
 type jsonEncoding struct{}
 
 // Convert converts src to dest using json encoding.
@@ -44,21 +69,108 @@ func (e *jsonEncoding) Convert(src, dest any) error {
 
 type fastEncoding struct{}
 
-// Convert converts src to dest using fast encoding.
-func (e *fastEncoding) Convert(src, dest any) error {
+// Convert converts src to dest using fast encoding. Struct fields are
+// matched by their exact names unless CaseInsensitive is given, and named
+// by their Go names instead of their tags with MatchByFieldName. Like
+// json.Unmarshal, a non-nil map in dest is merged into: the keys of src
+// replace their values, and the other keys are kept. With ReplaceMaps,
+// such a map is replaced by a new one holding only the keys of src.
+func (e *fastEncoding) Convert(src, dest any, opts ...Option) error {
+	l := newMiddleValueList()
+	defer middleValueListPool.Put(l)
+	return l.convert(src, dest, false, opts)
+}
+
+// ConvertContext converts src to dest like Convert, but checks ctx every
+// so often while walking src and returns ctx.Err() once ctx is done, which
+// bounds the work spent on huge or deeply nested values.
+func (e *fastEncoding) ConvertContext(ctx context.Context, src, dest any, opts ...Option) error {
+	l := newMiddleValueList()
+	defer middleValueListPool.Put(l)
+	if ctx.Done() != nil {
+		l.ctx = ctx
+	}
+	return l.convert(src, dest, false, opts)
+}
+
+// Merge converts src to dest like Convert, but reuses the backing arrays
+// of the slices in dest when they are large enough. Like Convert, keys of
+// a map in dest that are not in src are kept.
+func (e *fastEncoding) Merge(src, dest any, opts ...Option) error {
+	l := newMiddleValueList()
+	defer middleValueListPool.Put(l)
+	return l.convert(src, dest, true, opts)
+}
+
+// FastConverter converts values like FAST does, but keeps its buffer
+// between calls instead of taking one from a pool for each call. It is
+// not safe for concurrent use, so hold one per goroutine.
+type FastConverter struct {
+	l *MiddleValueList
+}
+
+// NewFastConverter returns a FastConverter with a pre-warmed buffer.
+func NewFastConverter() *FastConverter {
+	return &FastConverter{l: &MiddleValueList{
+		List: make([]MiddleValue, 1, 256),
+	}}
+}
+
+// Convert converts src to dest like FAST.Convert.
+func (c *FastConverter) Convert(src, dest any, opts ...Option) error {
+	c.l.Reset()
+	return c.l.convert(src, dest, false, opts)
+}
+
+// Merge converts src to dest like FAST.Merge.
+func (c *FastConverter) Merge(src, dest any, opts ...Option) error {
+	c.l.Reset()
+	return c.l.convert(src, dest, true, opts)
+}
+
+func (l *MiddleValueList) convert(src, dest any, merge bool, opts []Option) error {
 	srcValue := reflect.ValueOf(src)
-	if !srcValue.IsValid() || srcValue.IsNil() {
+	if !srcValue.IsValid() || (srcValue.Kind() == reflect.Ptr && srcValue.IsNil()) {
 		return nil
 	}
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
-		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(destValue)}
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dest)}
 	}
-	l := newMiddleValueList()
-	defer middleValueListPool.Put(l)
+	if t := destValue.Type().Elem(); !decodable(t) {
+		return fmt.Errorf("cannot decode into %s", t)
+	}
+	for _, opt := range opts {
+		opt(&l.arg)
+	}
+	l.merge = merge
 	reflectValue(l, 0, srcValue)
+	if l.ctxErr != nil {
+		return l.ctxErr
+	}
+	if l.err != nil {
+		return l.err
+	}
+	if l.ctx != nil {
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+	}
 	fromMiddleValue(l, l.List[0], destValue)
-	return nil
+	return l.err
+}
+
+// decodable reports whether a value of type t can be the target of a
+// conversion, pointers are followed to the type they point to.
+func decodable(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return false
+	}
+	return true
 }
 
 type ValueType int
@@ -70,13 +182,172 @@ const (
 	MapValueType   = ValueType(3)
 )
 
+// KindOf returns the ValueType FAST gives to i: NilValueType for nil and
+// nil pointers, slices and maps, SliceValueType for slices and arrays,
+// MapValueType for maps and structs, and ValueValueType for scalars and
+// the values kept as they are, like time.Time, []byte and json marshalers.
+func KindOf(i any) ValueType {
+	switch s := i.(type) {
+	case nil:
+		return NilValueType
+	case bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, string, time.Time:
+		return ValueValueType
+	case []interface{}:
+		if s == nil {
+			return NilValueType
+		}
+		return SliceValueType
+	case map[string]interface{}:
+		if s == nil {
+			return NilValueType
+		}
+		return MapValueType
+	}
+	v := reflect.ValueOf(i)
+	for {
+		t := v.Type()
+		k := t.Kind()
+		if k != reflect.Interface && t.Implements(valuerType) ||
+			t == timeType || t == rawMessageType ||
+			k != reflect.Interface && k != reflect.Pointer && isMarshaler(t) {
+			if k == reflect.Pointer && v.IsNil() {
+				return NilValueType
+			}
+			return ValueValueType
+		}
+		switch k {
+		case reflect.Interface, reflect.Pointer:
+			if v.IsNil() {
+				return NilValueType
+			}
+			if k == reflect.Pointer && isMarshaler(t) && !isMarshaler(t.Elem()) {
+				return ValueValueType
+			}
+			v = v.Elem()
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+			return ValueValueType
+		case reflect.Slice:
+			if v.IsNil() {
+				return NilValueType
+			}
+			if isByteSlice(t) {
+				return ValueValueType
+			}
+			return SliceValueType
+		case reflect.Array:
+			return SliceValueType
+		case reflect.Map:
+			if v.IsNil() {
+				return NilValueType
+			}
+			return MapValueType
+		case reflect.Struct:
+			return MapValueType
+		default:
+			return NilValueType
+		}
+	}
+}
+
 type MiddleValueList struct {
 	List []MiddleValue
+
+	// Keep track of what pointers we've seen in the current recursive call
+	// path, to avoid cycles that could lead to a stack overflow. Only do
+	// the relatively expensive map operations if ptrLevel is larger than
+	// startDetectingCyclesAfter, so that we skip the work if we're within a
+	// reasonable amount of nested pointers deep.
+	ptrLevel uint
+	ptrSeen  map[any]struct{}
+	err      error
+	arg      OptionArg
+	merge    bool
+
+	// The context of ConvertContext is checked each time the list grows by
+	// checkContextEvery values, so the walk stays cheap between checks.
+	ctx     context.Context
+	ctxErr  error
+	ctxNext int
+
+	// The fields of planType resolved in advance by a Decoder, used instead
+	// of looking them up in fieldCache.
+	planType   reflect.Type
+	planFields *structFields
 }
 
+const startDetectingCyclesAfter = 1000
+
+const checkContextEvery = 1024
+
 func (b *MiddleValueList) Reset() {
 	b.List[0] = MiddleValue{} // root
 	b.List = b.List[:1]
+	b.ptrLevel = 0
+	clear(b.ptrSeen)
+	b.err = nil
+	b.arg = OptionArg{}
+	b.merge = false
+	b.ctx = nil
+	b.ctxErr = nil
+	b.ctxNext = 0
+	b.planType = nil
+	b.planFields = nil
+}
+
+// canceled reports whether the context of the conversion is done, it only
+// asks the context once every checkContextEvery values.
+func (b *MiddleValueList) canceled() bool {
+	if b.ctx == nil {
+		return false
+	}
+	if b.ctxErr == nil && len(b.List) >= b.ctxNext {
+		b.ctxNext = len(b.List) + checkContextEvery
+		b.ctxErr = b.ctx.Err()
+	}
+	return b.ctxErr != nil
+}
+
+// enter records ptr on the current path once the nesting is deep enough,
+// and reports false when ptr is already on the path, which means a cycle.
+func (b *MiddleValueList) enter(v reflect.Value, ptr any) bool {
+	if b.ptrLevel++; b.ptrLevel <= startDetectingCyclesAfter {
+		return true
+	}
+	if _, ok := b.ptrSeen[ptr]; ok {
+		if b.err == nil {
+			b.err = &json.UnsupportedValueError{
+				Value: v,
+				Str:   fmt.Sprintf("encountered a cycle via %s", v.Type()),
+			}
+		}
+		b.ptrLevel--
+		return false
+	}
+	if b.ptrSeen == nil {
+		b.ptrSeen = make(map[any]struct{})
+	}
+	b.ptrSeen[ptr] = struct{}{}
+	return true
+}
+
+// saveError saves the first err it is called with, the conversion goes on
+// so that as many values as possible are converted.
+func (b *MiddleValueList) saveError(err error) {
+	if b.err == nil && err != nil {
+		b.err = err
+	}
+}
+
+// leave removes ptr from the current path.
+func (b *MiddleValueList) leave(ptr any) {
+	if b.ptrLevel > startDetectingCyclesAfter {
+		delete(b.ptrSeen, ptr)
+	}
+	b.ptrLevel--
 }
 
 type MiddleValue struct {
@@ -111,7 +382,22 @@ func valueEncoder(v reflect.Value) encoderFunc {
 	return typeEncoder(v.Type())
 }
 
-var encoderCache sync.Map // map[string]string
+var encoderCache sync.Map // map[reflect.Type]encoderFunc
+
+// ResetCaches drops the struct fields and the encoders cached for the
+// types seen so far, so that the types of an unloaded plugin can be
+// collected. They are rebuilt on demand, conversions running meanwhile
+// are not affected.
+func ResetCaches() {
+	fieldCache.Range(func(k, _ any) bool {
+		fieldCache.Delete(k)
+		return true
+	})
+	encoderCache.Range(func(k, _ any) bool {
+		encoderCache.Delete(k)
+		return true
+	})
+}
 
 type encoderFunc func(l *MiddleValueList, current int, v reflect.Value)
 
@@ -144,14 +430,153 @@ func typeEncoder(t reflect.Type) encoderFunc {
 	return f
 }
 
+// validMapKey returns the string form of a map key. Like encoding/json,
+// only string, encoding.TextMarshaler and integer keys are supported,
+// fmt.Stringer is accepted as a last resort.
 func validMapKey(key reflect.Value) (string, bool) {
-	if key.Kind() != reflect.String {
-		return "", false
+	if key.Kind() == reflect.String {
+		return key.String(), true
+	}
+	if tm, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Pointer && key.IsNil() {
+			return "", true
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), true
+	}
+	if s, ok := key.Interface().(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return "", false
+}
+
+// mapKeyValue parses a string key back into a value of the map key type.
+func mapKeyValue(key string, keyType reflect.Type) (reflect.Value, bool) {
+	if reflect.PointerTo(keyType).Implements(textUnmarshalerType) {
+		kv := reflect.New(keyType)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, false
+		}
+		return kv.Elem(), true
+	}
+	kv := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		kv.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || kv.OverflowInt(n) {
+			return reflect.Value{}, false
+		}
+		kv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || kv.OverflowUint(n) {
+			return reflect.Value{}, false
+		}
+		kv.SetUint(n)
+	default:
+		return reflect.Value{}, false
+	}
+	return kv, true
+}
+
+var (
+	timeType               = reflect.TypeOf(time.Time{})
+	rawMessageType         = reflect.TypeOf(json.RawMessage{})
+	mapStringInterfaceType = reflect.TypeOf(map[string]interface{}{})
+	jsonMarshalerType      = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType    = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	valuerType             = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType            = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isScanner reports whether a value of t, once its pointers are
+// allocated, is filled by the sql.Scanner interface.
+func isScanner(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reflect.PointerTo(t).Implements(scannerType)
+}
+
+// marshalsTo reports whether encoding/json honors the MarshalJSONTo
+// method, which json.Number has and promotes to the structs embedding it.
+// Only the json v2 based encoding/json does, so it's probed once.
+var marshalsTo = func() bool {
+	b, err := json.Marshal(struct{ json.Number }{"0"})
+	return err == nil && string(b) == "0"
+}()
+
+// isMarshaler reports whether t marshals itself, in which case the
+// value is kept as a whole instead of being walked field by field.
+func isMarshaler(t reflect.Type) bool {
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if !marshalsTo {
+		return false
 	}
-	return key.String(), true
+	m, ok := t.MethodByName("MarshalJSONTo")
+	if !ok {
+		return false
+	}
+	in := m.Type.NumIn()
+	if t.Kind() != reflect.Interface {
+		in-- // the receiver
+	}
+	return in == 1 && m.Type.NumOut() == 1 && m.Type.Out(0) == errorType
+}
+
+// isByteSlice reports whether t is a slice of bytes that json encodes as
+// a base64 string, FAST keeps such a slice as a single value.
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 &&
+		!isMarshaler(reflect.PointerTo(t.Elem()))
 }
 
 func newTypeEncoder(t reflect.Type) encoderFunc {
+	if t.Kind() != reflect.Interface && t.Implements(valuerType) {
+		return func(l *MiddleValueList, current int, v reflect.Value) {
+			if v.Kind() == reflect.Pointer && v.IsNil() {
+				l.List[current] = MiddleValue{Type: NilValueType}
+				return
+			}
+			r, err := v.Interface().(driver.Valuer).Value()
+			if err != nil {
+				l.saveError(err)
+			}
+			if r == nil {
+				l.List[current] = MiddleValue{Type: NilValueType}
+				return
+			}
+			l.List[current] = MiddleValue{Type: ValueValueType, Value: reflect.ValueOf(r)}
+		}
+	}
+	if t == timeType || t == rawMessageType || (t.Kind() != reflect.Interface && t.Kind() != reflect.Pointer && isMarshaler(t)) {
+		return func(l *MiddleValueList, current int, v reflect.Value) {
+			l.List[current] = MiddleValue{Type: ValueValueType, Value: v}
+		}
+	}
+	if isByteSlice(t) {
+		return func(l *MiddleValueList, current int, v reflect.Value) {
+			if v.IsNil() {
+				l.List[current] = MiddleValue{Type: NilValueType}
+				return
+			}
+			l.List[current] = MiddleValue{Type: ValueValueType, Value: v}
+		}
+	}
 	switch t.Kind() {
 	case reflect.Interface:
 		return func(l *MiddleValueList, current int, v reflect.Value) {
@@ -162,13 +587,27 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 			reflectValue(l, current, v.Elem())
 		}
 	case reflect.Pointer:
+		if isMarshaler(t) && !isMarshaler(t.Elem()) {
+			return func(l *MiddleValueList, current int, v reflect.Value) {
+				if v.IsNil() {
+					l.List[current] = MiddleValue{Type: NilValueType}
+					return
+				}
+				l.List[current] = MiddleValue{Type: ValueValueType, Value: v}
+			}
+		}
 		toMiddleValue := typeEncoder(t.Elem())
 		return func(l *MiddleValueList, current int, v reflect.Value) {
 			if v.IsNil() {
 				l.List[current] = MiddleValue{Type: NilValueType}
 				return
 			}
+			ptr := v.UnsafePointer()
+			if !l.enter(v, ptr) {
+				return
+			}
 			toMiddleValue(l, current, v.Elem())
+			l.leave(ptr)
 		}
 	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
@@ -179,18 +618,37 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 	case reflect.Array, reflect.Slice:
 		toMiddleValue := typeEncoder(t.Elem())
 		return func(l *MiddleValueList, current int, v reflect.Value) {
+			if v.Kind() == reflect.Slice && v.IsNil() {
+				l.List[current] = MiddleValue{Type: NilValueType}
+				return
+			}
 			n := v.Len()
 			p := &l.List[current]
 			p.Type = SliceValueType
 			if n == 0 {
 				return
 			}
+			if v.Kind() == reflect.Slice {
+				// Here we use a struct to memorize the pointer to the first
+				// element of the slice and its length.
+				ptr := struct {
+					ptr any // always an unsafe.Pointer, but avoids a dependency on package unsafe
+					len int
+				}{v.UnsafePointer(), n}
+				if !l.enter(v, ptr) {
+					return
+				}
+				defer l.leave(ptr)
+			}
 			p.Length = n
 			end := len(l.List)
 			p.First = end
 			for i := 0; i < n; i++ {
 				l.List = append(l.List, MiddleValue{})
 			}
+			if l.canceled() {
+				return
+			}
 			for i := 0; i < n; i++ {
 				toMiddleValue(l, end+i, v.Index(i))
 			}
@@ -198,33 +656,62 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 	case reflect.Map:
 		toMiddleValue := typeEncoder(t.Elem())
 		return func(l *MiddleValueList, current int, v reflect.Value) {
+			if v.IsNil() {
+				l.List[current] = MiddleValue{Type: NilValueType}
+				return
+			}
 			n := v.Len()
 			p := &l.List[current]
 			p.Type = MapValueType
 			if n == 0 {
 				return
 			}
+			ptr := v.UnsafePointer()
+			if !l.enter(v, ptr) {
+				return
+			}
+			defer l.leave(ptr)
 			p.Length = n
 			end := len(l.List)
 			p.First = end
 			for i := 0; i < n; i++ {
 				l.List = append(l.List, MiddleValue{})
 			}
+			if l.canceled() {
+				return
+			}
 			i := 0
+			// Ranging over a map[string]interface{} natively spares the
+			// copies of keys and values that MapRange makes, and leaves
+			// hold the values unboxed so that Interface() doesn't copy.
+			if t == mapStringInterfaceType && v.CanInterface() {
+				for key, val := range v.Interface().(map[string]interface{}) {
+					reflectValue(l, end+i, reflect.ValueOf(val))
+					l.List[end+i].Name = key
+					i++
+				}
+				return
+			}
 			iter := v.MapRange()
 			for iter.Next() {
 				strKey, valid := validMapKey(iter.Key())
 				if !valid {
+					l.saveError(fmt.Errorf("unsupported map key type %s", t.Key()))
 					continue
 				}
 				toMiddleValue(l, end+i, iter.Value())
 				l.List[end+i].Name = strKey
 				i++
 			}
+			l.List[current].Length = i
 		}
 	case reflect.Struct:
-		fields := cachedTypeFields(t)
+		jsonFields := cachedTypeFields(t)
 		return func(l *MiddleValueList, current int, v reflect.Value) {
+			fields := jsonFields
+			if l.arg.TagKey != "" || l.arg.MatchByFieldName {
+				fields = cachedArgFields(t, &l.arg)
+			}
 			n := len(fields.list)
 			p := &l.List[current]
 			p.Type = MapValueType
@@ -237,6 +724,10 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 			for i := 0; i < n; i++ {
 				l.List = append(l.List, MiddleValue{})
 			}
+			if l.canceled() {
+				return
+			}
+			i := 0
 			for j := range fields.list {
 				f := &fields.list[j]
 				fv := v
@@ -252,12 +743,21 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 					fv = fv.Field(i)
 				}
 				if breakNil {
-					l.List[end+j] = MiddleValue{Type: NilValueType, Name: f.name}
+					l.List[end+i] = MiddleValue{Type: NilValueType, Name: f.name}
+					i++
+					continue
+				}
+				if f.omitEmpty && isEmptyValue(fv) {
 					continue
 				}
-				f.encoder(l, end+j, fv)
-				l.List[end+j].Name = f.name
+				f.encoder(l, end+i, fv)
+				if f.quoted && l.List[end+i].Type == ValueValueType {
+					l.List[end+i].Value = quoteValue(l.List[end+i].Value)
+				}
+				l.List[end+i].Name = f.name
+				i++
 			}
+			l.List[current].Length = i
 		}
 	default:
 		return func(l *MiddleValueList, current int, v reflect.Value) {}
@@ -265,25 +765,51 @@ func newTypeEncoder(t reflect.Type) encoderFunc {
 }
 
 func fromMiddleValue(l *MiddleValueList, p MiddleValue, destValue reflect.Value) {
+	if p.Type != NilValueType && isScanner(destValue.Type()) {
+		destValue = makeValue(destValue)
+		scanner := destValue.Addr().Interface().(sql.Scanner)
+		l.saveError(scanner.Scan(valueInterface(l, p)))
+		return
+	}
+	if p.Type != NilValueType && destValue.Type() == rawMessageType {
+		if p.Type != ValueValueType || p.Value.Type() != rawMessageType {
+			l.saveError(fromRawMessage(l, p, destValue))
+			return
+		}
+	}
 	switch p.Type {
 	case NilValueType:
 		return
 	case ValueValueType:
-		fromSimple(p.Value, destValue)
+		fromSimple(l, p.Value, destValue)
 	case SliceValueType:
 		fromSlice(l, p, destValue)
 	case MapValueType:
 		fromMap(l, p, destValue)
 	default:
-		log.Println("should never reach here")
+		l.saveError(fmt.Errorf("unknown value type %d", p.Type))
 	}
 }
 
+// fromRawMessage stores the json encoding of p into a json.RawMessage,
+// a json.RawMessage source is copied verbatim by fromSimple instead.
+func fromRawMessage(l *MiddleValueList, p MiddleValue, destValue reflect.Value) error {
+	b, err := json.Marshal(valueInterface(l, p))
+	if err != nil {
+		return err
+	}
+	destValue.SetBytes(b)
+	return nil
+}
+
 func valueInterface(l *MiddleValueList, p MiddleValue) interface{} {
 	switch p.Type {
 	case NilValueType:
 		return nil
 	case ValueValueType:
+		if p.Value.Kind() == reflect.Slice {
+			return cloneBytes(p.Value).Interface()
+		}
 		return p.Value.Interface()
 	case SliceValueType:
 		data := l.List[p.First : p.First+p.Length]
@@ -292,7 +818,7 @@ func valueInterface(l *MiddleValueList, p MiddleValue) interface{} {
 		data := l.List[p.First : p.First+p.Length]
 		return objectInterface(l, data)
 	default:
-		log.Println("should never reach here")
+		l.saveError(fmt.Errorf("unknown value type %d", p.Type))
 		return nil
 	}
 }
@@ -313,9 +839,24 @@ func objectInterface(l *MiddleValueList, pm []MiddleValue) map[string]interface{
 	return r
 }
 
-func fromSimple(pv reflect.Value, destValue reflect.Value) {
+func fromSimple(l *MiddleValueList, pv reflect.Value, destValue reflect.Value) {
 	destValue = makeValue(destValue)
-	destValue.Set(pv)
+	if isByteSlice(pv.Type()) {
+		fromBytes(l, pv, destValue)
+		return
+	}
+	if pv.Kind() == reflect.Pointer && pv.Elem().Type().AssignableTo(destValue.Type()) {
+		pv = pv.Elem()
+	}
+	if pv.Type().AssignableTo(destValue.Type()) {
+		destValue.Set(pv)
+		return
+	}
+	if isMarshaler(pv.Type()) {
+		l.saveError(fromMarshaler(pv, destValue))
+		return
+	}
+	l.saveError(fromScalar(pv, destValue))
 
 	//switch c := item[0]; c {
 	//case 't', 'f': // true, false
@@ -409,6 +950,123 @@ func fromSimple(pv reflect.Value, destValue reflect.Value) {
 	//}
 }
 
+// fromBytes decodes the byte slice pv into destValue. A byte slice gets a
+// copy of pv, a string gets its bytes, or their base64 encoding with the
+// Base64Bytes option, and other slices and arrays get the bytes one by one.
+func fromBytes(l *MiddleValueList, pv reflect.Value, destValue reflect.Value) {
+	switch {
+	case destValue.Kind() == reflect.Interface && destValue.NumMethod() == 0:
+		destValue.Set(cloneBytes(pv))
+	case isByteSlice(destValue.Type()):
+		destValue.Set(cloneBytes(pv).Convert(destValue.Type()))
+	case destValue.Kind() == reflect.String:
+		if l.arg.Base64Bytes {
+			destValue.SetString(base64.StdEncoding.EncodeToString(pv.Bytes()))
+		} else {
+			destValue.SetString(string(pv.Bytes()))
+		}
+	case destValue.Kind() == reflect.Slice || destValue.Kind() == reflect.Array:
+		first, n := len(l.List), pv.Len()
+		for i := 0; i < n; i++ {
+			l.List = append(l.List, MiddleValue{Type: ValueValueType, Value: pv.Index(i)})
+		}
+		fromSlice(l, MiddleValue{Type: SliceValueType, First: first, Length: n}, destValue)
+	default:
+		if isMarshaler(pv.Type()) {
+			l.saveError(fromMarshaler(pv, destValue))
+			return
+		}
+		l.saveError(fmt.Errorf("unable to cast type (%s) to %s", pv.Type(), destValue.Type()))
+	}
+}
+
+// cloneBytes returns a copy of the byte slice v of the same type.
+func cloneBytes(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return v
+	}
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+	return c
+}
+
+// fromScalar casts pv to the kind of destValue by the scalar functions,
+// such as ToInt64E, when the types of them are different.
+func fromScalar(pv reflect.Value, destValue reflect.Value) error {
+	i := basicValue(pv)
+	switch destValue.Kind() {
+	case reflect.Bool:
+		b, err := ToBoolE(i)
+		if err != nil {
+			return err
+		}
+		destValue.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := ToInt64E(i)
+		if err != nil {
+			return err
+		}
+		if destValue.OverflowInt(n) {
+			return fmt.Errorf("value %v overflows %s", n, destValue.Type())
+		}
+		destValue.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := ToUint64E(i)
+		if err != nil {
+			return err
+		}
+		if destValue.OverflowUint(n) {
+			return fmt.Errorf("value %v overflows %s", n, destValue.Type())
+		}
+		destValue.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := ToFloat64E(i)
+		if err != nil {
+			return err
+		}
+		if destValue.OverflowFloat(n) {
+			return fmt.Errorf("value %v overflows %s", n, destValue.Type())
+		}
+		destValue.SetFloat(n)
+	case reflect.String:
+		destValue.SetString(ToString(i))
+	default:
+		return fmt.Errorf("unable to cast type (%s) to %s", pv.Type(), destValue.Type())
+	}
+	return nil
+}
+
+// basicValue returns pv as a value of its basic kind, so that the numeric
+// kinds of named types like `type Count int` can be cast as well.
+func basicValue(pv reflect.Value) any {
+	switch pv.Kind() {
+	case reflect.Bool:
+		return pv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return pv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return pv.Uint()
+	case reflect.Float32:
+		return float32(pv.Float())
+	case reflect.Float64:
+		return pv.Float()
+	case reflect.String:
+		return pv.String()
+	default:
+		return pv.Interface()
+	}
+}
+
+// fromMarshaler decodes the marshaled form of pv into destValue, just
+// like a json.Marshal followed by a json.Unmarshal does.
+func fromMarshaler(pv reflect.Value, destValue reflect.Value) error {
+	b, err := json.Marshal(pv.Interface())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, destValue.Addr().Interface())
+}
+
 func fromSlice(l *MiddleValueList, p MiddleValue, destValue reflect.Value) {
 	var data []MiddleValue
 	if p.Length > 0 {
@@ -419,18 +1077,29 @@ func fromSlice(l *MiddleValueList, p MiddleValue, destValue reflect.Value) {
 	case reflect.Interface:
 		arr := arrayInterface(l, data)
 		destValue.Set(reflect.ValueOf(arr))
-	default:
+	case reflect.Slice, reflect.Array:
 		n := len(data)
 		if destValue.Kind() == reflect.Slice {
-			v := reflect.MakeSlice(destValue.Type(), n, n)
-			destValue.Set(v)
+			if l.merge && !destValue.IsNil() && destValue.Cap() >= n {
+				destValue.SetLen(n)
+			} else {
+				v := reflect.MakeSlice(destValue.Type(), n, n)
+				destValue.Set(v)
+			}
+		}
+		// Elements that don't fit in a fixed-size array are dropped, it's
+		// an error only with the Strict option.
+		if n > destValue.Len() {
+			if l.arg.Strict {
+				l.saveError(fmt.Errorf("unable to cast %d elements to %s", n, destValue.Type()))
+			}
+			n = destValue.Len()
 		}
 		i := 0
 		for ; i < n; i++ {
-			if i < destValue.Len() {
-				fromMiddleValue(l, l.List[p.First+i], destValue.Index(i))
-			}
+			fromMiddleValue(l, l.List[p.First+i], destValue.Index(i))
 		}
+		// The rest of a fixed-size array is zeroed.
 		if i < destValue.Len() {
 			if destValue.Kind() == reflect.Array {
 				z := reflect.Zero(destValue.Type().Elem())
@@ -441,6 +1110,8 @@ func fromSlice(l *MiddleValueList, p MiddleValue, destValue reflect.Value) {
 				destValue.SetLen(i)
 			}
 		}
+	default:
+		l.saveError(fmt.Errorf("unable to cast slice to %s", destValue.Type()))
 	}
 }
 
@@ -456,59 +1127,165 @@ func fromMap(l *MiddleValueList, p MiddleValue, destValue reflect.Value) {
 		oi := objectInterface(l, data)
 		destValue.Set(reflect.ValueOf(oi))
 	case reflect.Map:
-		if dstType.Key().Kind() != reflect.String {
+		// map[string]interface{} is filled without reflection, which saves
+		// an allocation for each of the values.
+		if dstType == mapStringInterfaceType {
+			if destValue.IsNil() || l.arg.ReplaceMaps {
+				destValue.Set(reflect.ValueOf(make(map[string]interface{}, p.Length)))
+			}
+			m := destValue.Interface().(map[string]interface{})
+			for i := 0; i < p.Length; i++ {
+				e := l.List[p.First+i]
+				m[e.Name] = valueInterface(l, e)
+			}
 			return
 		}
-		if destValue.IsNil() {
-			destValue.Set(reflect.MakeMap(dstType))
+		if destValue.IsNil() || l.arg.ReplaceMaps {
+			destValue.Set(reflect.MakeMapWithSize(dstType, p.Length))
 		}
 		fromMapToMap(l, p, destValue, dstType)
 	case reflect.Struct:
 		fromMapToStruct(l, p, destValue, dstType)
+	default:
+		l.saveError(fmt.Errorf("unable to cast map to %s", dstType))
 	}
 }
 
 func fromMapToMap(l *MiddleValueList, p MiddleValue, destValue reflect.Value, dstType reflect.Type) {
-	elemType := dstType.Elem()
+	keyType, elemType := dstType.Key(), dstType.Elem()
 	for i := 0; i < p.Length; i++ {
+		e := l.List[p.First+i]
+		keyValue, ok := mapKeyValue(e.Name, keyType)
+		if !ok {
+			l.saveError(fmt.Errorf("unable to cast map key %q to %s", e.Name, keyType))
+			continue
+		}
+		// elemValue is settable, so a pointer elem type gets allocated
+		// by makeValue when the value is decoded into it.
 		elemValue := reflect.New(elemType).Elem()
-		fromMiddleValue(l, l.List[p.First+i], elemValue)
-		keyValue := reflect.ValueOf(p.Name)
+		fromMiddleValue(l, e, elemValue)
 		destValue.SetMapIndex(keyValue, elemValue)
 	}
 }
 
 func fromMapToStruct(l *MiddleValueList, p MiddleValue, destValue reflect.Value, dstType reflect.Type) {
-	fields := cachedTypeFields(dstType)
+	var fields structFields
+	if dstType == l.planType {
+		fields = *l.planFields
+	} else {
+		fields = cachedArgFields(dstType, &l.arg)
+	}
+	var found map[*field]struct{}
+	if l.arg.ApplyDefaults && fields.hasDefaults {
+		found = make(map[*field]struct{}, p.Length)
+	}
 	for i := 0; i < p.Length; i++ {
 		e := l.List[p.First+i]
 		f, ok := fields.byExactName[e.Name]
+		if !ok && l.arg.CaseInsensitive {
+			f, ok = fields.byFoldedName[foldName(e.Name)]
+		}
 		if !ok {
 			continue
 		}
-		subValue := destValue
-		for _, j := range f.index {
-			if subValue.Kind() == reflect.Ptr {
-				if subValue.IsNil() {
-					if !subValue.CanSet() {
-						subValue = reflect.Value{}
-						break
-					}
-					subValue.Set(reflect.New(subValue.Type().Elem()))
+		if found != nil {
+			found[f] = struct{}{}
+		}
+		subValue := fieldValue(l, destValue, f)
+		if !subValue.IsValid() {
+			continue
+		}
+		if f.quoted && e.Type == ValueValueType && e.Value.Kind() == reflect.String {
+			e.Value = unquoteValue(e.Value, f.typ)
+		}
+		fromMiddleValue(l, e, subValue)
+	}
+	if found == nil {
+		return
+	}
+	for j := range fields.list {
+		f := &fields.list[j]
+		if !f.hasDefault {
+			continue
+		}
+		if _, ok := found[f]; ok {
+			continue
+		}
+		subValue := fieldValue(l, destValue, f)
+		if !subValue.IsValid() {
+			continue
+		}
+		e := MiddleValue{Type: ValueValueType, Value: reflect.ValueOf(f.defValue)}
+		fromMiddleValue(l, e, subValue)
+	}
+}
+
+// fieldValue returns the field f of the struct v, allocating the embedded
+// pointers on its way, or an invalid value when one of them can't be set.
+func fieldValue(l *MiddleValueList, v reflect.Value, f *field) reflect.Value {
+	for _, j := range f.index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					l.saveError(fmt.Errorf("cannot set embedded pointer to unexported struct: %v", v.Type().Elem()))
+					return reflect.Value{}
 				}
-				subValue = subValue.Elem()
+				v.Set(reflect.New(v.Type().Elem()))
 			}
-			subValue = subValue.Field(j)
+			v = v.Elem()
 		}
-		fromMiddleValue(l, e, subValue)
+		v = v.Field(j)
+	}
+	return v
+}
+
+// isEmptyValue reports whether v is an empty value, that is false, 0,
+// a nil pointer or interface, and any array, slice, map or string of
+// length zero.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// quoteValue returns the string form of a value of a field tagged with
+// the ",string" option, a string value is quoted once more as JSON does.
+func quoteValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.String {
+		b, _ := json.Marshal(v.String())
+		return reflect.ValueOf(string(b))
+	}
+	return reflect.ValueOf(ToString(basicValue(v)))
+}
+
+// unquoteValue reverts quoteValue for a string field, the string form of
+// the other kinds is parsed later by the scalar functions.
+func unquoteValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if t.Kind() != reflect.String {
+		return v
+	}
+	var s string
+	if err := json.Unmarshal([]byte(v.String()), &s); err != nil {
+		return v
 	}
+	return reflect.ValueOf(s)
 }
 
 func makeValue(v reflect.Value) reflect.Value {
 	for {
+		// Load value from interface, but only if the result will be
+		// usefully addressable, like a pointer set by the caller.
 		if v.Kind() == reflect.Interface && !v.IsNil() {
 			e := v.Elem()
-			if e.Kind() == reflect.Ptr && !e.IsNil() && e.Elem().Kind() == reflect.Ptr {
+			if e.Kind() == reflect.Ptr && !e.IsNil() {
 				v = e
 				continue
 			}
@@ -534,11 +1311,33 @@ func makeValue(v reflect.Value) reflect.Value {
 	return v
 }
 
+// tagOptions is the string following a comma in a struct field's "json"
+// tag, or the empty string. It does not include the leading comma.
+type tagOptions string
+
 // parseTag splits a struct field's json tag into its name and
 // comma-separated options.
-func parseTag(tag string) string {
-	tag, _, _ = strings.Cut(tag, ",")
-	return tag
+func parseTag(tag string) (string, tagOptions) {
+	tag, opt, _ := strings.Cut(tag, ",")
+	return tag, tagOptions(opt)
+}
+
+// Contains reports whether a comma-separated list of options
+// contains a particular substr flag. substr must be surrounded by a
+// string boundary or commas.
+func (o tagOptions) Contains(optionName string) bool {
+	if len(o) == 0 {
+		return false
+	}
+	s := string(o)
+	for s != "" {
+		var name string
+		name, s, _ = strings.Cut(s, ",")
+		if name == optionName {
+			return true
+		}
+	}
+	return false
 }
 
 func isValidTag(s string) bool {
@@ -560,16 +1359,30 @@ func isValidTag(s string) bool {
 
 // A field represents a single field found in a struct.
 type field struct {
-	name    string
-	tag     bool
-	index   []int
-	typ     reflect.Type
-	encoder encoderFunc
+	name      string
+	tag       bool
+	index     []int
+	typ       reflect.Type
+	quoted    bool
+	omitEmpty bool
+	encoder   encoderFunc
+
+	// The default tag, applied to a missing field with ApplyDefaults.
+	defValue   string
+	hasDefault bool
 }
 
 type structFields struct {
-	list        []field
-	byExactName map[string]*field
+	list         []field
+	byExactName  map[string]*field
+	byFoldedName map[string]*field
+	hasDefaults  bool
+}
+
+// foldName returns a folded string such that foldName(x) == foldName(y)
+// is identical to strings.EqualFold(x, y).
+func foldName(name string) string {
+	return strings.ToLower(strings.ToUpper(name))
 }
 
 // byIndex sorts field by index sequence.
@@ -591,21 +1404,75 @@ func (x byIndex) Less(i, j int) bool {
 	return len(x[i].index) < len(x[j].index)
 }
 
-var fieldCache sync.Map // map[reflect.Type]structFields
+var fieldCache sync.Map // map[fieldCacheKey]structFields
+
+// fieldCacheKey is the key of fieldCache, an empty tagKey means "json".
+// byName means the fields are named by their Go names.
+type fieldCacheKey struct {
+	typ    reflect.Type
+	tagKey string
+	byName bool
+}
+
+// FieldNames returns the names FAST gives to the fields of the struct type
+// t, or of the struct t points to, in field order. The names honor the
+// json tags, or the TagKey tags when given, or the Go names with
+// MatchByFieldName, and promote the fields of
+// embedded structs the way encoding/json does. It returns nil when t is
+// not a struct.
+func FieldNames(t reflect.Type, opts ...Option) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	fields := cachedArgFields(t, &arg)
+	names := make([]string, len(fields.list))
+	for i := range fields.list {
+		names[i] = fields.list[i].name
+	}
+	return names
+}
 
 // cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
 func cachedTypeFields(t reflect.Type) structFields {
-	if f, ok := fieldCache.Load(t); ok {
+	return cachedTagFields(t, "")
+}
+
+// cachedTagFields is like cachedTypeFields but names the fields by the
+// tagKey tag first, then by the json tag.
+func cachedTagFields(t reflect.Type, tagKey string) structFields {
+	return cachedFields(fieldCacheKey{typ: t, tagKey: tagKey})
+}
+
+// cachedArgFields is like cachedTagFields but names the fields as the
+// TagKey and MatchByFieldName options of arg say.
+func cachedArgFields(t reflect.Type, arg *OptionArg) structFields {
+	if arg.MatchByFieldName {
+		return cachedFields(fieldCacheKey{typ: t, byName: true})
+	}
+	return cachedTagFields(t, arg.TagKey)
+}
+
+func cachedFields(key fieldCacheKey) structFields {
+	if f, ok := fieldCache.Load(key); ok {
 		return f.(structFields)
 	}
-	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	f, _ := fieldCache.LoadOrStore(key, typeFields(key.typ, key.tagKey, key.byName))
 	return f.(structFields)
 }
 
 // typeFields returns a list of fields that JSON should recognize for the given type.
 // The algorithm is breadth-first search over the set of structs to include - the top struct
-// and then any reachable anonymous structs.
-func typeFields(t reflect.Type) structFields {
+// and then any reachable anonymous structs. A field is named by its tagKey tag when it has
+// one, otherwise by its json tag. When byName is true a field is named by its Go name, the
+// tag only decides whether the field is skipped and its options.
+func typeFields(t reflect.Type, tagKey string, byName bool) structFields {
 	// Anonymous fields to explore at the current level and the next.
 	var current []field
 	next := []field{{typ: t}}
@@ -647,12 +1514,18 @@ func typeFields(t reflect.Type) structFields {
 					// Ignore unexported non-embedded fields.
 					continue
 				}
-				tag := sf.Tag.Get("json")
+				tag, ok := "", false
+				if tagKey != "" {
+					tag, ok = sf.Tag.Lookup(tagKey)
+				}
+				if !ok {
+					tag = sf.Tag.Get("json")
+				}
 				if tag == "-" {
 					continue
 				}
-				name := parseTag(tag)
-				if !isValidTag(name) {
+				name, opts := parseTag(tag)
+				if byName || !isValidTag(name) {
 					name = ""
 				}
 				index := make([]int, len(f.index)+1)
@@ -672,10 +1545,24 @@ func typeFields(t reflect.Type) structFields {
 						name = sf.Name
 					}
 					field := field{
-						name:  name,
-						tag:   tagged,
-						index: index,
-						typ:   ft,
+						name:      name,
+						tag:       tagged,
+						index:     index,
+						typ:       ft,
+						omitEmpty: opts.Contains("omitempty"),
+					}
+					field.defValue, field.hasDefault = sf.Tag.Lookup("default")
+
+					// Only strings, floats, integers, and booleans can be quoted.
+					if opts.Contains("string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64,
+							reflect.String:
+							field.quoted = true
+						}
 					}
 
 					fields = append(fields, field)
@@ -751,11 +1638,18 @@ func typeFields(t reflect.Type) structFields {
 		f.encoder = typeEncoder(typeByIndex(t, f.index))
 	}
 
+	hasDefaults := false
 	exactNameIndex := make(map[string]*field, len(fields))
+	foldedNameIndex := make(map[string]*field, len(fields))
 	for i, field := range fields {
+		hasDefaults = hasDefaults || field.hasDefault
 		exactNameIndex[field.name] = &fields[i]
+		// The first folded match takes precedence, as encoding/json does.
+		if _, ok := foldedNameIndex[foldName(field.name)]; !ok {
+			foldedNameIndex[foldName(field.name)] = &fields[i]
+		}
 	}
-	return structFields{fields, exactNameIndex}
+	return structFields{fields, exactNameIndex, foldedNameIndex, hasDefaults}
 }
 
 func typeByIndex(t reflect.Type, index []int) reflect.Type {
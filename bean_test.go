@@ -17,9 +17,13 @@
 package cast_test
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -639,3 +643,1330 @@ func TestJsonEncoding(t *testing.T) {
 		assert.Equal(t, *b.Text, a.Text)
 	})
 }
+
+func TestFastEncodingTime(t *testing.T) {
+
+	now := time.Date(2023, 1, 2, 15, 4, 5, 6, time.UTC)
+
+	t.Run("time", func(t *testing.T) {
+		type Src struct {
+			At  time.Time  `json:"at"`
+			Ptr *time.Time `json:"ptr"`
+		}
+		type Dest struct {
+			At  time.Time  `json:"at"`
+			Ptr *time.Time `json:"ptr"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(Src{At: now, Ptr: &now}, &dest)
+		assert.Nil(t, err)
+		assert.True(t, dest.At.Equal(now))
+		assert.True(t, dest.Ptr.Equal(now))
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		type Src struct {
+			At time.Time `json:"at"`
+		}
+		var dest struct {
+			At interface{} `json:"at"`
+		}
+		err := cast.FAST.Convert(&Src{At: now}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest.At, now)
+		var v interface{}
+		err = cast.FAST.Convert(&Src{At: now}, &v)
+		assert.Nil(t, err)
+		assert.Equal(t, v, map[string]interface{}{"at": now})
+	})
+
+	t.Run("string", func(t *testing.T) {
+		type Src struct {
+			At time.Time `json:"at"`
+		}
+		type Dest struct {
+			At string `json:"at"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(&Src{At: now}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest.At, "2023-01-02T15:04:05.000000006Z")
+	})
+
+	t.Run("root", func(t *testing.T) {
+		var dest time.Time
+		err := cast.FAST.Convert(now, &dest)
+		assert.Nil(t, err)
+		assert.True(t, dest.Equal(now))
+	})
+}
+
+type Level int
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string{"debug", "info", "warn"}[l])
+}
+
+type Cents int64
+
+func (c *Cents) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%02d", *c/100, *c%100)), nil
+}
+
+type Amount struct {
+	Text string
+}
+
+func (a *Amount) UnmarshalText(b []byte) error {
+	a.Text = "$" + string(b)
+	return nil
+}
+
+func TestFastEncodingMarshaler(t *testing.T) {
+
+	price := Cents(123)
+
+	type Src struct {
+		Level Level  `json:"level"`
+		Price *Cents `json:"price"`
+		Empty *Cents `json:"empty"`
+	}
+
+	t.Run("same type", func(t *testing.T) {
+		var dest Src
+		err := cast.FAST.Convert(Src{Level: 1, Price: &price}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, Src{Level: 1, Price: &price})
+	})
+
+	t.Run("unmarshaler", func(t *testing.T) {
+		type Dest struct {
+			Level string  `json:"level"`
+			Price Amount  `json:"price"`
+			Empty *Amount `json:"empty"`
+		}
+		var d1, d2 Dest
+		err := cast.FAST.Convert(Src{Level: 2, Price: &price}, &d1)
+		assert.Nil(t, err)
+		err = cast.JSON.Convert(Src{Level: 2, Price: &price}, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, Dest{Level: "warn", Price: Amount{Text: "$1.23"}})
+		assert.Equal(t, d1, d2)
+	})
+}
+
+func TestFastEncodingMapKey(t *testing.T) {
+
+	t.Run("int", func(t *testing.T) {
+		src := map[int]string{1: "a", 2: "b"}
+		var d1 map[string]string
+		err := cast.FAST.Convert(src, &d1)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, map[string]string{"1": "a", "2": "b"})
+		var d2 map[int64]string
+		err = cast.FAST.Convert(src, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d2, map[int64]string{1: "a", 2: "b"})
+	})
+
+	t.Run("uint", func(t *testing.T) {
+		src := map[uint8]int{1: 1, 255: 2}
+		var d1 map[uint16]int
+		err := cast.FAST.Convert(src, &d1)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, map[uint16]int{1: 1, 255: 2})
+		var d2 map[int8]int
+		err = cast.FAST.Convert(src, &d2)
+		assert.Error(t, err, "unable to cast map key \"255\" to int8")
+		assert.Equal(t, d2, map[int8]int{1: 1})
+	})
+
+	t.Run("stringer", func(t *testing.T) {
+		src := map[*bytes.Buffer]int{bytes.NewBufferString("a"): 1}
+		var dest map[string]int
+		err := cast.FAST.Convert(src, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, map[string]int{"a": 1})
+	})
+
+	t.Run("named string", func(t *testing.T) {
+		type MyKey string
+		var d1 map[MyKey]int
+		err := cast.FAST.Convert(map[string]int{"a": 1, "b": 2}, &d1)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, map[MyKey]int{"a": 1, "b": 2})
+		var d2 map[string]int
+		err = cast.FAST.Convert(d1, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d2, map[string]int{"a": 1, "b": 2})
+		type Row struct {
+			Attrs map[MyKey]string `json:"attrs"`
+		}
+		var d3 Row
+		err = cast.FAST.Convert(map[string]any{"attrs": map[string]any{"k": "v"}}, &d3)
+		assert.Nil(t, err)
+		assert.Equal(t, d3, Row{Attrs: map[MyKey]string{"k": "v"}})
+	})
+}
+
+func TestFastEncodingCycle(t *testing.T) {
+
+	t.Run("pointer", func(t *testing.T) {
+		type Node struct {
+			Name string `json:"name"`
+			Next *Node  `json:"next"`
+		}
+		n := &Node{Name: "a"}
+		n.Next = n
+		var dest Node
+		err := cast.FAST.Convert(n, &dest)
+		assert.Error(t, err, "json: unsupported value: encountered a cycle via \\*cast_test.Node")
+	})
+
+	t.Run("map", func(t *testing.T) {
+		m := map[string]interface{}{}
+		m["self"] = m
+		var dest interface{}
+		err := cast.FAST.Convert(m, &dest)
+		assert.Error(t, err, "encountered a cycle via map\\[string\\]interface {}")
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		s := []interface{}{nil}
+		s[0] = s
+		var dest interface{}
+		err := cast.FAST.Convert(s, &dest)
+		assert.Error(t, err, "encountered a cycle via \\[\\]interface {}")
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		type Pair struct {
+			A *int `json:"a"`
+			B *int `json:"b"`
+		}
+		var dest Pair
+		v := cast.IntPtr(3)
+		err := cast.FAST.Convert(Pair{A: v, B: v}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, *dest.A, 3)
+		assert.Equal(t, *dest.B, 3)
+	})
+}
+
+func TestFastEncodingMismatch(t *testing.T) {
+
+	t.Run("coerce", func(t *testing.T) {
+		type Src struct {
+			A int     `json:"a"`
+			B string  `json:"b"`
+			C float64 `json:"c"`
+			D string  `json:"d"`
+		}
+		type Dest struct {
+			A string `json:"a"`
+			B int8   `json:"b"`
+			C uint   `json:"c"`
+			D bool   `json:"d"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(Src{A: 1, B: "2", C: 3, D: "yes"}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, Dest{A: "1", B: 2, C: 3, D: true})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		type Src struct {
+			A string `json:"a"`
+			B int    `json:"b"`
+		}
+		type Dest struct {
+			A int  `json:"a"`
+			B int8 `json:"b"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(Src{A: "abc", B: 300}, &dest)
+		assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+		err = cast.FAST.Convert(Src{A: "1", B: 300}, &dest)
+		assert.Error(t, err, "value 300 overflows int8")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		type Src struct {
+			A int `json:"a"`
+		}
+		type Dest struct {
+			A []int `json:"a"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(Src{A: 1}, &dest)
+		assert.Error(t, err, "unable to cast type \\(int\\) to \\[\\]int")
+	})
+}
+
+func TestFastEncodingNumber(t *testing.T) {
+
+	type Count int
+	type Ratio float32
+
+	type Src struct {
+		Count  int     `json:"count"`
+		Total  Count   `json:"total"`
+		Ratio  float64 `json:"ratio"`
+		Weight Ratio   `json:"weight"`
+	}
+	type Dest struct {
+		Count  float64 `json:"count"`
+		Total  uint16  `json:"total"`
+		Ratio  Ratio   `json:"ratio"`
+		Weight string  `json:"weight"`
+	}
+
+	src := Src{Count: 3, Total: 7, Ratio: 0.5, Weight: 0.1}
+	var d1 Dest
+	err := cast.FAST.Convert(src, &d1)
+	assert.Nil(t, err)
+	assert.Equal(t, d1, Dest{Count: 3, Total: 7, Ratio: 0.5, Weight: "0.1"})
+
+	var d2 struct {
+		Count Count `json:"count"`
+	}
+	err = cast.FAST.Convert(map[string]interface{}{"count": 3.0}, &d2)
+	assert.Nil(t, err)
+	assert.Equal(t, d2.Count, Count(3))
+}
+
+func TestFastEncodingQuoted(t *testing.T) {
+
+	type Src struct {
+		ID    int64   `json:"id,string"`
+		Ok    bool    `json:"ok,string"`
+		Rate  float64 `json:"rate,string"`
+		Name  string  `json:"name,string"`
+		Count *int    `json:"count,string"`
+	}
+
+	src := Src{ID: 123, Ok: true, Rate: 1.5, Name: "abc", Count: cast.IntPtr(3)}
+
+	t.Run("interface", func(t *testing.T) {
+		var d1, d2 map[string]interface{}
+		err := cast.FAST.Convert(src, &d1)
+		assert.Nil(t, err)
+		err = cast.JSON.Convert(src, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, d2)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		var d1, d2 Src
+		err := cast.FAST.Convert(src, &d1)
+		assert.Nil(t, err)
+		err = cast.JSON.Convert(src, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, src)
+		assert.Equal(t, d1, d2)
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		type Dest struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		var dest Dest
+		err := cast.FAST.Convert(src, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, Dest{ID: 123, Name: "\"abc\""})
+	})
+}
+
+func TestFastEncodingOmitEmpty(t *testing.T) {
+
+	type Src struct {
+		Name  string            `json:"name,omitempty"`
+		Age   int               `json:"age,omitempty"`
+		Ok    bool              `json:"ok,omitempty"`
+		Ptr   *int              `json:"ptr,omitempty"`
+		List  []int             `json:"list,omitempty"`
+		Attrs map[string]string `json:"attrs,omitempty"`
+		Keep  int               `json:"keep"`
+	}
+
+	for _, src := range []Src{
+		{},
+		{Name: "a", Age: 3, Ok: true, Ptr: cast.IntPtr(0), List: []int{1}, Attrs: map[string]string{"a": "b"}},
+	} {
+		var d1, d2 map[string]interface{}
+		err := cast.FAST.Convert(src, &d1)
+		assert.Nil(t, err)
+		err = cast.JSON.Convert(src, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, len(d1), len(d2))
+		for k := range d2 {
+			_, ok := d1[k]
+			assert.True(t, ok)
+		}
+
+		var s1 Src
+		err = cast.FAST.Convert(src, &s1)
+		assert.Nil(t, err)
+		assert.Equal(t, s1, src)
+	}
+}
+
+func TestFastEncodingRawMessage(t *testing.T) {
+
+	type Envelope struct {
+		Kind    string          `json:"kind"`
+		Payload json.RawMessage `json:"payload"`
+		Extra   json.RawMessage `json:"extra"`
+	}
+
+	src := Envelope{Kind: "a", Payload: json.RawMessage(`{"x": [1, 2]}`)}
+
+	t.Run("struct", func(t *testing.T) {
+		var dest Envelope
+		err := cast.FAST.Convert(src, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, src)
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		var dest map[string]interface{}
+		err := cast.FAST.Convert(src, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest["payload"], src.Payload)
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		m := map[string]interface{}{
+			"kind":    "a",
+			"payload": map[string]interface{}{"x": []int{1, 2}},
+		}
+		var dest Envelope
+		err := cast.FAST.Convert(m, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, string(dest.Payload), `{"x":[1,2]}`)
+		assert.Nil(t, dest.Extra)
+	})
+}
+
+func TestFastEncodingSQL(t *testing.T) {
+
+	type Row struct {
+		Name  sql.NullString `json:"name"`
+		Age   sql.NullInt64  `json:"age"`
+		Score sql.NullInt64  `json:"score"`
+	}
+
+	t.Run("scanner", func(t *testing.T) {
+		var row Row
+		src := map[string]interface{}{"name": "a", "age": 3}
+		err := cast.FAST.Convert(src, &row)
+		assert.Nil(t, err)
+		assert.Equal(t, row, Row{
+			Name: sql.NullString{String: "a", Valid: true},
+			Age:  sql.NullInt64{Int64: 3, Valid: true},
+		})
+	})
+
+	t.Run("valuer", func(t *testing.T) {
+		src := Row{
+			Name: sql.NullString{String: "a", Valid: true},
+			Age:  sql.NullInt64{Int64: 3, Valid: true},
+		}
+		var m map[string]interface{}
+		err := cast.FAST.Convert(src, &m)
+		assert.Nil(t, err)
+		assert.Equal(t, m, map[string]interface{}{"name": "a", "age": int64(3), "score": nil})
+
+		var row Row
+		err = cast.FAST.Convert(src, &row)
+		assert.Nil(t, err)
+		assert.Equal(t, row, src)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var row Row
+		err := cast.FAST.Convert(map[string]interface{}{"age": "abc"}, &row)
+		assert.Error(t, err, "converting driver.Value type string \\(\"abc\"\\) to a int64")
+	})
+}
+
+func TestFastEncodingError(t *testing.T) {
+
+	t.Run("map key", func(t *testing.T) {
+		var dest map[string]int
+		err := cast.FAST.Convert(map[float64]int{1.5: 1}, &dest)
+		assert.Error(t, err, "unsupported map key type float64")
+	})
+
+	t.Run("map", func(t *testing.T) {
+		var dest []int
+		err := cast.FAST.Convert(map[string]int{"a": 1}, &dest)
+		assert.Error(t, err, "unable to cast map to \\[\\]int")
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		var dest map[string]int
+		err := cast.FAST.Convert([]int{1}, &dest)
+		assert.Error(t, err, "unable to cast slice to map\\[string\\]int")
+	})
+
+	t.Run("dest", func(t *testing.T) {
+		var dest int
+		err := cast.FAST.Convert(1, dest)
+		assert.Error(t, err, "json: Unmarshal\\(non-pointer int\\)")
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		var ch chan int
+		err := cast.FAST.Convert(1, &ch)
+		assert.Error(t, err, "cannot decode into chan int")
+
+		var fn *func()
+		err = cast.FAST.Convert("f", &fn)
+		assert.Error(t, err, "cannot decode into \\*func\\(\\)")
+	})
+}
+
+func TestFastEncodingPointerMap(t *testing.T) {
+
+	type Foo struct {
+		Name  string          `json:"name"`
+		Items map[string]*int `json:"items"`
+	}
+
+	src := map[string]interface{}{
+		"a": map[string]interface{}{"name": "a", "items": map[string]int{"x": 1}},
+		"b": nil,
+	}
+
+	var dest map[string]*Foo
+	err := cast.FAST.Convert(src, &dest)
+	assert.Nil(t, err)
+	assert.Equal(t, dest, map[string]*Foo{
+		"a": {Name: "a", Items: map[string]*int{"x": cast.IntPtr(1)}},
+		"b": nil,
+	})
+
+	var nested map[string]map[string]*Foo
+	err = cast.FAST.Convert(map[string]interface{}{"n": src}, &nested)
+	assert.Nil(t, err)
+	assert.Equal(t, nested["n"], dest)
+}
+
+func TestFastEncodingCaseInsensitive(t *testing.T) {
+
+	type Dest struct {
+		Name    string `json:"name"`
+		UserID  int    `json:"userId"`
+		Country string
+	}
+
+	src := map[string]interface{}{"Name": "a", "USERID": 3, "country": "cn"}
+
+	var strict Dest
+	err := cast.FAST.Convert(src, &strict)
+	assert.Nil(t, err)
+	assert.Equal(t, strict, Dest{})
+
+	var dest Dest
+	err = cast.FAST.Convert(src, &dest, cast.CaseInsensitive())
+	assert.Nil(t, err)
+	assert.Equal(t, dest, Dest{Name: "a", UserID: 3, Country: "cn"})
+}
+
+func TestFastEncodingArray(t *testing.T) {
+
+	t.Run("shorter", func(t *testing.T) {
+		dest := [3]int{7, 8, 9}
+		err := cast.FAST.Convert([]int{1}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, [3]int{1, 0, 0})
+	})
+
+	t.Run("longer", func(t *testing.T) {
+		var dest [2]int
+		err := cast.FAST.Convert([]int{1, 2, 3}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, [2]int{1, 2})
+
+		err = cast.FAST.Convert([]int{1, 2, 3}, &dest, cast.Strict())
+		assert.Error(t, err, "unable to cast 3 elements to \\[2\\]int")
+	})
+
+	t.Run("source", func(t *testing.T) {
+		var d1 []int64
+		err := cast.FAST.Convert([3]int{1, 2, 3}, &d1)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, []int64{1, 2, 3})
+
+		var d2 [3]string
+		err = cast.FAST.Convert([3]string{"a", "b", "c"}, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d2, [3]string{"a", "b", "c"})
+
+		type Box struct {
+			Items [2]int `json:"items"`
+		}
+		var d3 map[string]interface{}
+		err = cast.FAST.Convert(Box{Items: [2]int{1, 2}}, &d3)
+		assert.Nil(t, err)
+		assert.Equal(t, d3, map[string]interface{}{"items": []interface{}{1, 2}})
+	})
+}
+
+func TestFastEncodingMerge(t *testing.T) {
+
+	t.Run("slice", func(t *testing.T) {
+		dest := make([]int, 1, 4)
+		p := &dest[:4][0]
+		err := cast.FAST.Merge([]string{"1", "2", "3"}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, []int{1, 2, 3})
+		assert.True(t, &dest[0] == p)
+
+		err = cast.FAST.Merge([]int{4}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, []int{4})
+		assert.Equal(t, cap(dest), 4)
+
+		err = cast.FAST.Merge([]int{1, 2, 3, 4, 5}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, []int{1, 2, 3, 4, 5})
+		assert.False(t, &dest[0] == p)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		dest := map[string][]int{"a": {1}, "b": {2}}
+		err := cast.FAST.Merge(map[string][]int{"b": {3}, "c": {4}}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, dest, map[string][]int{"a": {1}, "b": {3}, "c": {4}})
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		dest := make([]int, 0, 4)
+		err := cast.FAST.Convert([]int{1}, &dest)
+		assert.Nil(t, err)
+		assert.Equal(t, cap(dest), 1)
+	})
+}
+
+func TestFastConverter(t *testing.T) {
+
+	var src *TwitterStruct
+	err := json.Unmarshal([]byte(TwitterJson), &src)
+	assert.Nil(t, err)
+
+	c := cast.NewFastConverter()
+	for i := 0; i < 3; i++ {
+		var d1, d2 TwitterStruct
+		err = c.Convert(src, &d1)
+		assert.Nil(t, err)
+		err = cast.FAST.Convert(src, &d2)
+		assert.Nil(t, err)
+		assert.Equal(t, d1, d2)
+	}
+
+	var dest [1]int
+	err = c.Convert([]int{1, 2}, &dest, cast.Strict())
+	assert.Error(t, err, "unable to cast 2 elements to \\[1\\]int")
+
+	// options and errors don't leak into the next call
+	err = c.Convert([]int{1, 2}, &dest)
+	assert.Nil(t, err)
+
+	s := make([]int, 0, 2)
+	err = c.Merge([]int{1, 2}, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s, []int{1, 2})
+	assert.Equal(t, cap(s), 2)
+}
+
+func BenchmarkFastConverter(b *testing.B) {
+
+	var src *TwitterStruct
+	if err := json.Unmarshal([]byte(TwitterJson), &src); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("FAST", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dest TwitterStruct
+			_ = cast.FAST.Convert(src, &dest)
+		}
+	})
+
+	b.Run("converter", func(b *testing.B) {
+		b.ReportAllocs()
+		c := cast.NewFastConverter()
+		for i := 0; i < b.N; i++ {
+			var dest TwitterStruct
+			_ = c.Convert(src, &dest)
+		}
+	})
+}
+
+func BenchmarkFastEncodingInterface(b *testing.B) {
+
+	var src *TwitterStruct
+	if err := json.Unmarshal([]byte(TwitterJson), &src); err != nil {
+		b.Fatal(err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(TwitterJson), &m); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("struct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dest map[string]any
+			_ = cast.FAST.Convert(src, &dest)
+		}
+	})
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dest map[string]any
+			_ = cast.FAST.Convert(m, &dest)
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dest []any
+			_ = cast.FAST.Convert(src.Statuses, &dest)
+		}
+	})
+}
+
+func TestFastEncodingTagKey(t *testing.T) {
+
+	type Inner struct {
+		Port int `cfg:"port"`
+	}
+	type Config struct {
+		Name  string `cfg:"name" json:"json_name"`
+		Host  string `json:"host"`
+		Skip  string `cfg:"-" json:"skip"`
+		Level int
+		Inner Inner `cfg:"inner"`
+	}
+
+	src := map[string]interface{}{
+		"name":  "a",
+		"host":  "localhost",
+		"skip":  "b",
+		"Level": 3,
+		"inner": map[string]interface{}{"port": 8080},
+	}
+
+	var c1 Config
+	err := cast.FAST.Convert(src, &c1, cast.TagKey("cfg"))
+	assert.Nil(t, err)
+	assert.Equal(t, c1, Config{Name: "a", Host: "localhost", Level: 3, Inner: Inner{Port: 8080}})
+
+	var c2 Config
+	err = cast.FAST.Convert(src, &c2)
+	assert.Nil(t, err)
+	assert.Equal(t, c2, Config{Host: "localhost", Skip: "b", Level: 3})
+
+	var m map[string]interface{}
+	err = cast.FAST.Convert(c1, &m, cast.TagKey("cfg"))
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]interface{}{
+		"name":  "a",
+		"host":  "localhost",
+		"Level": 3,
+		"inner": map[string]interface{}{"port": 8080},
+	})
+
+	c3, err := cast.To[Config](src, cast.TagKey("cfg"))
+	assert.Nil(t, err)
+	assert.Equal(t, c3, c1)
+}
+
+func TestResetCaches(t *testing.T) {
+
+	type Stu struct {
+		Name string `json:"name"`
+	}
+
+	var s1 Stu
+	err := cast.FAST.Convert(map[string]interface{}{"name": "a"}, &s1)
+	assert.Nil(t, err)
+
+	cast.ResetCaches()
+
+	var s2 Stu
+	err = cast.FAST.Convert(map[string]interface{}{"name": "a"}, &s2)
+	assert.Nil(t, err)
+	assert.Equal(t, s2, s1)
+}
+
+func TestFastEncodingInterfaceMap(t *testing.T) {
+
+	var src *TwitterStruct
+	err := json.Unmarshal([]byte(TwitterJson), &src)
+	assert.Nil(t, err)
+
+	var d1, d2 map[string]any
+	err = cast.FAST.Convert(src, &d1)
+	assert.Nil(t, err)
+	err = cast.JSON.Convert(src, &d2)
+	assert.Nil(t, err)
+	assert.Equal(t, d1, d2)
+
+	type Inner struct {
+		Tags []string `json:"tags"`
+	}
+	type Outer struct {
+		Inner  Inner             `json:"inner"`
+		List   []Inner           `json:"list"`
+		Ptr    *Inner            `json:"ptr"`
+		Nested map[string]*Inner `json:"nested"`
+		Attrs  map[string]int    `json:"attrs"`
+	}
+
+	var d3 map[string]any
+	err = cast.FAST.Convert(Outer{
+		Inner:  Inner{Tags: []string{"a"}},
+		List:   []Inner{{Tags: []string{"b"}}},
+		Nested: map[string]*Inner{"c": {}},
+	}, &d3)
+	assert.Nil(t, err)
+	assert.Equal(t, d3, map[string]any{
+		"inner":  map[string]any{"tags": []any{"a"}},
+		"list":   []any{map[string]any{"tags": []any{"b"}}},
+		"ptr":    nil,
+		"nested": map[string]any{"c": map[string]any{"tags": nil}},
+		"attrs":  nil,
+	})
+}
+
+func TestFastEncodingInterfaceDest(t *testing.T) {
+
+	type Foo struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	src := map[string]interface{}{"name": "a", "age": 3}
+
+	var d1 any = &Foo{}
+	err := cast.FAST.Convert(src, &d1)
+	assert.Nil(t, err)
+	assert.Equal(t, d1, &Foo{Name: "a", Age: 3})
+
+	var d2 any = Foo{}
+	err = cast.FAST.Convert(src, &d2)
+	assert.Nil(t, err)
+	assert.Equal(t, d2, map[string]interface{}{"name": "a", "age": 3})
+
+	type Sink struct {
+		Data any `json:"data"`
+	}
+	d3 := Sink{Data: &[]int{}}
+	err = cast.FAST.Convert(map[string]interface{}{"data": []string{"1", "2"}}, &d3)
+	assert.Nil(t, err)
+	assert.Equal(t, d3.Data, &[]int{1, 2})
+
+	var d4 any = (*Foo)(nil)
+	err = cast.FAST.Convert(src, &d4)
+	assert.Nil(t, err)
+	assert.Equal(t, d4, map[string]interface{}{"name": "a", "age": 3})
+}
+
+func TestFastEncodingConvertContext(t *testing.T) {
+
+	src := make([]map[string]interface{}, 5000)
+	for i := range src {
+		src[i] = map[string]interface{}{"id": i}
+	}
+
+	var r []map[string]int
+	err := cast.FAST.ConvertContext(context.Background(), src, &r)
+	assert.Nil(t, err)
+	assert.Equal(t, len(r), 5000)
+	assert.Equal(t, r[4999]["id"], 4999)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var r2 []map[string]int
+	err = cast.FAST.ConvertContext(ctx, src, &r2)
+	assert.Error(t, err, "context canceled")
+	assert.Equal(t, len(r2), 0)
+
+	var i int
+	err = cast.FAST.ConvertContext(ctx, 3, &i)
+	assert.Error(t, err, "context canceled")
+
+	// the buffer goes back to the pool without the context
+	var r3 []map[string]int
+	err = cast.FAST.Convert(src, &r3)
+	assert.Nil(t, err)
+	assert.Equal(t, len(r3), 5000)
+}
+
+func TestFieldNames(t *testing.T) {
+
+	type Base struct {
+		ID      int    `json:"id"`
+		Created string `json:"created,omitempty"`
+	}
+	type User struct {
+		*Base
+		Name    string `json:"name" cfg:"user_name"`
+		Age     int
+		Ignored string `json:"-"`
+		private string
+		Created string `json:"created"`
+	}
+
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(User{})), []string{"id", "name", "Age", "created"})
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(&User{})), []string{"id", "name", "Age", "created"})
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(User{}), cast.TagKey("cfg")), []string{"id", "user_name", "Age", "created"})
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(3)), []string(nil))
+
+	names := cast.FieldNames(reflect.TypeOf(User{}))
+	names[0] = "changed"
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(User{}))[0], "id")
+}
+
+func TestFastEncodingApplyDefaults(t *testing.T) {
+
+	type Server struct {
+		Host    string `json:"host" default:"localhost"`
+		Port    int    `json:"port" default:"8080"`
+		Debug   bool   `json:"debug" default:"true"`
+		Retries uint8  `json:"retries" default:"3"`
+		Name    string `json:"name"`
+	}
+	type Config struct {
+		Server Server  `json:"server"`
+		Ratio  float64 `json:"ratio" default:"0.5"`
+	}
+
+	src := map[string]interface{}{
+		"server": map[string]interface{}{"port": "9090", "debug": false},
+	}
+
+	var c Config
+	err := cast.FAST.Convert(src, &c, cast.ApplyDefaults())
+	assert.Nil(t, err)
+	assert.Equal(t, c, Config{
+		Server: Server{Host: "localhost", Port: 9090, Debug: false, Retries: 3},
+		Ratio:  0.5,
+	})
+
+	var c2 Config
+	err = cast.FAST.Convert(src, &c2)
+	assert.Nil(t, err)
+	assert.Equal(t, c2, Config{Server: Server{Port: 9090}})
+
+	type Bad struct {
+		Port int `json:"port" default:"abc"`
+	}
+	var b Bad
+	err = cast.FAST.Convert(map[string]interface{}{}, &b, cast.ApplyDefaults())
+	assert.Error(t, err, "invalid syntax")
+}
+
+type EmbeddedInt int
+
+type EmbeddedString string
+
+type EmbeddedTags []string
+
+type EmbeddedAttrs map[string]string
+
+type embeddedInt int
+
+func TestFastEncodingEmbeddedNonStruct(t *testing.T) {
+
+	type Outer struct {
+		EmbeddedInt
+		*EmbeddedString
+		EmbeddedTags
+		EmbeddedAttrs `json:"attrs"`
+		embeddedInt
+		X int
+	}
+
+	s := EmbeddedString("s")
+	for _, src := range []Outer{
+		{
+			EmbeddedInt:    1,
+			EmbeddedString: &s,
+			EmbeddedTags:   EmbeddedTags{"a"},
+			EmbeddedAttrs:  EmbeddedAttrs{"k": "v"},
+			embeddedInt:    3,
+			X:              2,
+		},
+		{},
+	} {
+		var jm, fm map[string]interface{}
+		assert.Nil(t, cast.JSON.Convert(src, &jm))
+		assert.Nil(t, cast.FAST.Convert(src, &fm))
+		assert.Equal(t, cast.ToString(fm), cast.ToString(jm))
+	}
+
+	var d Outer
+	err := cast.FAST.Convert(map[string]interface{}{
+		"EmbeddedInt":    "1",
+		"EmbeddedString": "s",
+		"EmbeddedTags":   []interface{}{"a"},
+		"attrs":          map[string]interface{}{"k": "v"},
+		"embeddedInt":    3,
+	}, &d)
+	assert.Nil(t, err)
+	assert.Equal(t, d, Outer{
+		EmbeddedInt:    1,
+		EmbeddedString: &s,
+		EmbeddedTags:   EmbeddedTags{"a"},
+		EmbeddedAttrs:  EmbeddedAttrs{"k": "v"},
+	})
+
+	// Newer versions of encoding/json promote the MarshalJSONTo method of
+	// an embedded json.Number to the struct, FAST does whatever json does.
+	type WithNumber struct {
+		json.Number
+		X int
+	}
+
+	src := WithNumber{Number: "4.5", X: 1}
+	var jm, fm map[string]interface{}
+	jerr := cast.JSON.Convert(src, &jm)
+	ferr := cast.FAST.Convert(src, &fm)
+	assert.Equal(t, ferr == nil, jerr == nil)
+	assert.Equal(t, cast.ToString(fm), cast.ToString(jm))
+
+	var w WithNumber
+	err = cast.FAST.Convert(src, &w)
+	assert.Nil(t, err)
+	assert.Equal(t, w, src)
+}
+
+func TestFastEncodingReplaceMaps(t *testing.T) {
+
+	type Config struct {
+		Labels map[string]string      `json:"labels"`
+		Extra  map[string]interface{} `json:"extra"`
+	}
+
+	src := map[string]interface{}{
+		"labels": map[string]interface{}{"b": "3", "c": 4},
+		"extra":  map[string]interface{}{"y": 2},
+	}
+
+	labels := map[string]string{"a": "1", "b": "2"}
+	c := Config{Labels: labels, Extra: map[string]interface{}{"x": 1}}
+	err := cast.FAST.Convert(src, &c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Labels, map[string]string{"a": "1", "b": "3", "c": "4"})
+	assert.Equal(t, c.Extra, map[string]interface{}{"x": 1, "y": 2})
+
+	// json.Unmarshal merges the same way.
+	j := Config{Labels: map[string]string{"a": "1", "b": "2"}}
+	err = json.Unmarshal([]byte(`{"labels":{"b":"3","c":"4"}}`), &j)
+	assert.Nil(t, err)
+	assert.Equal(t, j.Labels, map[string]string{"a": "1", "b": "3", "c": "4"})
+
+	labels = map[string]string{"a": "1", "b": "2"}
+	c = Config{Labels: labels, Extra: map[string]interface{}{"x": 1}}
+	err = cast.FAST.Convert(src, &c, cast.ReplaceMaps())
+	assert.Nil(t, err)
+	assert.Equal(t, c.Labels, map[string]string{"b": "3", "c": "4"})
+	assert.Equal(t, c.Extra, map[string]interface{}{"y": 2})
+	assert.Equal(t, labels, map[string]string{"a": "1", "b": "2"})
+
+	m := map[int]int{1: 1}
+	err = cast.FAST.Convert(map[string]int{"2": 2}, &m, cast.ReplaceMaps())
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[int]int{2: 2})
+}
+
+func TestFastEncodingDashTag(t *testing.T) {
+
+	type S struct {
+		Ignored int `json:"-"`
+		Dash    int `json:"-,"`
+		Other   int `json:"-" cfg:"other"`
+		Hidden  int `json:"hidden" cfg:"-"`
+	}
+
+	src := S{Ignored: 1, Dash: 2, Other: 3, Hidden: 4}
+	b, err := json.Marshal(src)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"-":2,"hidden":4}`)
+
+	var m map[string]interface{}
+	err = cast.FAST.Convert(src, &m)
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]interface{}{"-": 2, "hidden": 4})
+
+	var s S
+	err = cast.FAST.Convert(map[string]interface{}{"-": 5, "Ignored": 6, "hidden": 7}, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s, S{Dash: 5, Hidden: 7})
+
+	m = nil
+	err = cast.FAST.Convert(src, &m, cast.TagKey("cfg"))
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]interface{}{"-": 2, "other": 3})
+}
+
+type kindMarshaler struct{ V int }
+
+func (m kindMarshaler) MarshalJSON() ([]byte, error) { return json.Marshal(m.V) }
+
+func TestKindOf(t *testing.T) {
+
+	type S struct{ A int }
+	type ID int64
+
+	var nilPtr *S
+	var nilMap map[string]int
+	var nilSlice []int
+	var nilAny []interface{}
+	var nilNull *sql.NullString
+
+	for _, c := range []struct {
+		i any
+		k cast.ValueType
+	}{
+		{nil, cast.NilValueType},
+		{nilPtr, cast.NilValueType},
+		{nilMap, cast.NilValueType},
+		{nilSlice, cast.NilValueType},
+		{nilAny, cast.NilValueType},
+		{nilNull, cast.NilValueType},
+		{func() {}, cast.NilValueType},
+		{3, cast.ValueValueType},
+		{"a", cast.ValueValueType},
+		{ID(3), cast.ValueValueType},
+		{cast.IntPtr(3), cast.ValueValueType},
+		{time.Now(), cast.ValueValueType},
+		{json.RawMessage(`{}`), cast.ValueValueType},
+		{kindMarshaler{}, cast.ValueValueType},
+		{sql.NullString{}, cast.ValueValueType},
+		{[]int{}, cast.SliceValueType},
+		{[2]int{}, cast.SliceValueType},
+		{[]byte("a"), cast.ValueValueType},
+		{[]interface{}{1}, cast.SliceValueType},
+		{map[string]interface{}{}, cast.MapValueType},
+		{map[int]int{}, cast.MapValueType},
+		{S{}, cast.MapValueType},
+		{&S{}, cast.MapValueType},
+	} {
+		assert.Equal(t, cast.KindOf(c.i), c.k)
+
+		// KindOf agrees with the value FAST converts i to.
+		var v interface{}
+		assert.Nil(t, cast.FAST.Convert(c.i, &v))
+		switch v.(type) {
+		case nil:
+			assert.True(t, c.k == cast.NilValueType || c.k == cast.ValueValueType)
+		case []interface{}:
+			assert.Equal(t, c.k, cast.SliceValueType)
+		case map[string]interface{}:
+			assert.Equal(t, c.k, cast.MapValueType)
+		default:
+			assert.Equal(t, c.k, cast.ValueValueType)
+		}
+	}
+}
+
+func TestFastEncodingBytes(t *testing.T) {
+
+	type Blob []byte
+
+	type S struct {
+		Data []byte `json:"data"`
+	}
+
+	src := []byte("hello")
+
+	var b []byte
+	err := cast.FAST.Convert(src, &b)
+	assert.Nil(t, err)
+	assert.Equal(t, b, src)
+	b[0] = 'H'
+	assert.Equal(t, string(src), "hello")
+
+	var blob Blob
+	err = cast.FAST.Convert(src, &blob)
+	assert.Nil(t, err)
+	assert.Equal(t, blob, Blob("hello"))
+
+	var s string
+	err = cast.FAST.Convert(src, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s, "hello")
+
+	err = cast.FAST.Convert(src, &s, cast.Base64Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, s, "aGVsbG8=")
+
+	var i interface{}
+	err = cast.FAST.Convert(src, &i)
+	assert.Nil(t, err)
+	assert.Equal(t, i, []byte("hello"))
+
+	var ints []int
+	err = cast.FAST.Convert([]byte{1, 2}, &ints)
+	assert.Nil(t, err)
+	assert.Equal(t, ints, []int{1, 2})
+
+	var arr [3]byte
+	err = cast.FAST.Convert([]byte{1, 2}, &arr)
+	assert.Nil(t, err)
+	assert.Equal(t, arr, [3]byte{1, 2, 0})
+
+	var m map[string]interface{}
+	err = cast.FAST.Convert(S{Data: src}, &m)
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]interface{}{"data": []byte("hello")})
+
+	var d S
+	err = cast.FAST.Convert(map[string]interface{}{"data": src}, &d)
+	assert.Nil(t, err)
+	assert.Equal(t, d, S{Data: []byte("hello")})
+
+	var n S
+	err = cast.FAST.Convert(S{}, &n)
+	assert.Nil(t, err)
+	assert.True(t, n.Data == nil)
+
+	var f float64
+	err = cast.FAST.Convert(src, &f)
+	assert.Error(t, err, "unable to cast type \\(\\[\\]uint8\\) to float64")
+
+	// JSON agrees on byte slices, apart from the base64 of strings.
+	var j []byte
+	err = cast.JSON.Convert(src, &j)
+	assert.Nil(t, err)
+	assert.Equal(t, string(j), "hello")
+}
+
+type CSV []string
+
+func (c CSV) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(c, ","))
+}
+
+func TestFastEncodingMarshalerSlice(t *testing.T) {
+
+	type Src struct {
+		A CSV `json:"a"`
+	}
+
+	var s struct {
+		A string `json:"a"`
+	}
+	err := cast.FAST.Convert(Src{A: CSV{"a", "b"}}, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s.A, "a,b")
+
+	var j struct {
+		A string `json:"a"`
+	}
+	err = cast.JSON.Convert(Src{A: CSV{"a", "b"}}, &j)
+	assert.Nil(t, err)
+	assert.Equal(t, j, s)
+
+	// Like JSON, a []byte takes the marshaled string as base64.
+	var b struct {
+		A []byte `json:"a"`
+	}
+	err = cast.FAST.Convert(Src{A: CSV{"a", "b"}}, &b)
+	assert.Error(t, err, "illegal base64 data")
+	err = cast.JSON.Convert(Src{A: CSV{"a", "b"}}, &b)
+	assert.Error(t, err, "illegal base64 data")
+
+	err = cast.FAST.Convert(Src{A: CSV{"aGk="}}, &b)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b.A), "hi")
+
+	var d []byte
+	err = cast.FAST.Convert(CSV{"aGk="}, &d)
+	assert.Nil(t, err)
+	assert.Equal(t, string(d), "hi")
+}
+
+func TestFastEncodingInterfaceSlice(t *testing.T) {
+
+	src := []any{1, "x", []any{2, nil}, map[string]any{"a": []any{3}}, nil}
+
+	var dest []any
+	err := cast.FAST.Convert(src, &dest)
+	assert.Nil(t, err)
+	assert.Equal(t, dest, src)
+
+	// Elements are boxed once, so they come out with their own types.
+	assert.Equal(t, reflect.TypeOf(dest[0]), reflect.TypeOf(0))
+	assert.Equal(t, reflect.TypeOf(dest[2]), reflect.TypeOf([]any{}))
+	assert.Equal(t, reflect.TypeOf(dest[2].([]any)[0]), reflect.TypeOf(0))
+	assert.Equal(t, reflect.TypeOf(dest[3].(map[string]any)["a"]), reflect.TypeOf([]any{}))
+
+	var i any
+	err = cast.FAST.Convert(src, &i)
+	assert.Nil(t, err)
+	assert.Equal(t, i, any(src))
+
+	p := &src
+	var d2 []any
+	err = cast.FAST.Convert(&p, &d2)
+	assert.Nil(t, err)
+	assert.Equal(t, d2, src)
+}
+
+func TestFastEncodingMatchByFieldName(t *testing.T) {
+
+	type Address struct {
+		City string `json:"city_name"`
+	}
+	type Model struct {
+		ID      int      `json:"id"`
+		Name    string   `json:"name"`
+		Secret  string   `json:"-"`
+		Tags    []string `json:"tags,omitempty"`
+		Address Address  `json:"address"`
+	}
+	type DTO struct {
+		ID      int64             `json:"user_id"`
+		Name    string            `json:"userName"`
+		Secret  string            `json:"secret"`
+		Tags    []string          `json:"labels"`
+		Address map[string]string `json:"addr"`
+	}
+
+	m := Model{ID: 1, Name: "a", Secret: "s", Tags: []string{"x"}, Address: Address{City: "b"}}
+
+	var d1 DTO
+	err := cast.FAST.Convert(m, &d1)
+	assert.Nil(t, err)
+	assert.Equal(t, d1, DTO{})
+
+	var d2 DTO
+	err = cast.FAST.Convert(m, &d2, cast.MatchByFieldName())
+	assert.Nil(t, err)
+	assert.Equal(t, d2, DTO{
+		ID:      1,
+		Name:    "a",
+		Tags:    []string{"x"},
+		Address: map[string]string{"City": "b"},
+	})
+
+	d3, err := cast.To[Model](d2, cast.MatchByFieldName())
+	assert.Nil(t, err)
+	assert.Equal(t, d3, Model{ID: 1, Name: "a", Tags: []string{"x"}, Address: Address{City: "b"}})
+
+	assert.Equal(t, cast.FieldNames(reflect.TypeOf(m), cast.MatchByFieldName()),
+		[]string{"ID", "Name", "Tags", "Address"})
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// ToBigInt casts an any to a *big.Int.
+// When type is clear, it is recommended to use standard library functions.
+func ToBigInt(i any) *big.Int {
+	v, _ := ToBigIntE(i)
+	return v
+}
+
+// ToBigIntE casts an any to a *big.Int. A float is truncated, a string or
+// a json.Number is parsed with base prefixes allowed. The result is never
+// shared with i.
+// When type is clear, it is recommended to use standard library functions.
+func ToBigIntE(i any) (*big.Int, error) {
+	switch s := i.(type) {
+	case nil:
+		return new(big.Int), nil
+	case *big.Int:
+		if s == nil {
+			return new(big.Int), nil
+		}
+		return new(big.Int).Set(s), nil
+	case *big.Float:
+		if s == nil {
+			return new(big.Int), nil
+		}
+		if s.IsInf() {
+			return nil, &ConvertError{Value: s, TargetType: "*big.Int", Reason: ReasonNotFinite}
+		}
+		r, _ := s.Int(nil)
+		return r, nil
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, &ConvertError{Value: f, TargetType: "*big.Int", Reason: ReasonNotFinite}
+		}
+		r, _ := big.NewFloat(f).Int(nil)
+		return r, nil
+	case reflect.String:
+		r, ok := new(big.Int).SetString(strings.TrimSpace(v.String()), 0)
+		if !ok {
+			return nil, &ConvertError{Value: v.String(), TargetType: "*big.Int", Reason: ReasonParse}
+		}
+		return r, nil
+	default:
+		return nil, unsupportedTypeError(i, "*big.Int")
+	}
+}
+
+// ToBigFloat casts an any to a *big.Float.
+// When type is clear, it is recommended to use standard library functions.
+func ToBigFloat(i any) *big.Float {
+	v, _ := ToBigFloatE(i)
+	return v
+}
+
+// ToBigFloatE casts an any to a *big.Float. A string or a json.Number is
+// parsed with a precision large enough to keep all of its digits. The
+// result is never shared with i.
+// When type is clear, it is recommended to use standard library functions.
+func ToBigFloatE(i any) (*big.Float, error) {
+	switch s := i.(type) {
+	case nil:
+		return new(big.Float), nil
+	case *big.Float:
+		if s == nil {
+			return new(big.Float), nil
+		}
+		return new(big.Float).Copy(s), nil
+	case *big.Int:
+		if s == nil {
+			return new(big.Float), nil
+		}
+		return new(big.Float).SetInt(s), nil
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) {
+			return nil, &ConvertError{Value: f, TargetType: "*big.Float", Reason: ReasonNotFinite}
+		}
+		return new(big.Float).SetFloat64(f), nil
+	case reflect.String:
+		s := strings.TrimSpace(v.String())
+		// about 3.32 bits are needed for each decimal digit
+		prec := uint(max(len(s)*4, 64))
+		r, _, err := big.ParseFloat(s, 0, prec, big.ToNearestEven)
+		if err != nil {
+			return nil, &ConvertError{Value: v.String(), TargetType: "*big.Float", Reason: ReasonParse}
+		}
+		return r, nil
+	default:
+		return nil, unsupportedTypeError(i, "*big.Float")
+	}
+}
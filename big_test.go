@@ -0,0 +1,81 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToBigInt(t *testing.T) {
+
+	assert.Equal(t, cast.ToBigInt(nil), big.NewInt(0))
+
+	assert.Equal(t, cast.ToBigInt(3), big.NewInt(3))
+	assert.Equal(t, cast.ToBigInt(cast.Int8Ptr(-3)), big.NewInt(-3))
+	assert.Equal(t, cast.ToBigInt(uint64(math.MaxUint64)).String(), "18446744073709551615")
+	assert.Equal(t, cast.ToBigInt(3.9), big.NewInt(3))
+	assert.Equal(t, cast.ToBigInt("0x10"), big.NewInt(16))
+	assert.Equal(t, cast.ToBigInt(json.Number("42")), big.NewInt(42))
+	assert.Equal(t, cast.ToBigInt(big.NewFloat(2.5)), big.NewInt(2))
+
+	b := big.NewInt(5)
+	r := cast.ToBigInt(b)
+	r.SetInt64(6)
+	assert.Equal(t, b, big.NewInt(5))
+
+	_, err := cast.ToBigIntE("1.5")
+	assert.Error(t, err, "unable to cast \"1.5\" to \\*big.Int")
+	_, err = cast.ToBigIntE(math.NaN())
+	assert.Error(t, err, "unable to cast NaN to \\*big.Int")
+	_, err = cast.ToBigIntE(true)
+	assert.Error(t, err, "unable to cast type \\(bool\\) to \\*big.Int")
+
+	v, err := cast.To[*big.Int]("123456789012345678901234567890")
+	assert.Nil(t, err)
+	assert.Equal(t, v.String(), "123456789012345678901234567890")
+}
+
+func TestToBigFloat(t *testing.T) {
+
+	assert.Equal(t, cast.ToBigFloat(nil).Sign(), 0)
+
+	assert.Equal(t, cast.ToBigFloat(3).String(), "3")
+	assert.Equal(t, cast.ToBigFloat(uint8(3)).String(), "3")
+	assert.Equal(t, cast.ToBigFloat(1.5).String(), "1.5")
+	assert.Equal(t, cast.ToBigFloat(big.NewInt(7)).String(), "7")
+	assert.Equal(t, cast.ToBigFloat(json.Number("2.25")).String(), "2.25")
+
+	s := "123456789012345678901234567890.123456789"
+	assert.Equal(t, cast.ToBigFloat(s).Text('f', 9), s)
+
+	_, err := cast.ToBigFloatE("abc")
+	assert.Error(t, err, "unable to cast \"abc\" to \\*big.Float")
+	_, err = cast.ToBigFloatE(math.NaN())
+	assert.Error(t, err, "unable to cast NaN to \\*big.Float")
+	_, err = cast.ToBigFloatE([]int{1})
+	assert.Error(t, err, "unable to cast type \\(\\[\\]int\\) to \\*big.Float")
+
+	v, err := cast.To[*big.Float]("0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, v.Text('g', 10), "0.1")
+}
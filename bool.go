@@ -17,20 +17,38 @@
 package cast
 
 import (
-	"fmt"
-	"strconv"
+	"encoding/json"
+	"strings"
 )
 
 // ToBool casts an any to a bool.
 // When type is clear, it is recommended to use standard library functions.
-func ToBool(i any) bool {
-	v, _ := ToBoolE(i)
+func ToBool(i any, opts ...Option) bool {
+	v, _ := ToBoolE(i, opts...)
 	return v
 }
 
-// ToBoolE casts an any to a bool.
+// ToBoolOr casts an any to a bool, it returns def when i is nil or the
+// cast fails.
+func ToBoolOr(i any, def bool, opts ...Option) bool {
+	if i == nil {
+		return def
+	}
+	v, err := ToBoolE(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ToBoolE casts an any to a bool. Numbers are true when nonzero. Strings
+// accept "1", "t", "T", "TRUE", "true", "True", "yes", "Yes", "YES", "on",
+// "On", "ON" as true and "0", "f", "F", "FALSE", "false", "False", "no",
+// "No", "NO", "off", "Off", "OFF" or "" as false, so does a blank string
+// with the EmptyAsZero option. With the NumericBool option, a string that
+// matches none of them is parsed as an integer, which is true when nonzero.
 // When type is clear, it is recommended to use standard library functions.
-func ToBoolE(i any) (bool, error) {
+func ToBoolE(i any, opts ...Option) (bool, error) {
 	switch b := i.(type) {
 	case nil:
 		return false, nil
@@ -83,14 +101,46 @@ func ToBoolE(i any) (bool, error) {
 	case *float64:
 		return *b != 0, nil
 	case string:
-		return strconv.ParseBool(b)
+		return parseBool(b, opts...)
 	case *string:
-		return strconv.ParseBool(*b)
+		return parseBool(*b, opts...)
 	case bool:
 		return b, nil
 	case *bool:
 		return *b, nil
+	case []byte:
+		return parseBool(string(b), opts...)
+	case json.Number:
+		f, err := b.Float64()
+		if err != nil {
+			return false, parseError(string(b), "bool", err)
+		}
+		return f != 0, nil
 	default:
-		return false, fmt.Errorf("unable to cast type (%T) to bool", i)
+		return false, unsupportedTypeError(i, "bool")
+	}
+}
+
+func parseBool(s string, opts ...Option) (bool, error) {
+	switch s {
+	case "1", "t", "T", "TRUE", "true", "True", "yes", "Yes", "YES", "on", "On", "ON":
+		return true, nil
+	case "0", "f", "F", "FALSE", "false", "False", "no", "No", "NO", "off", "Off", "OFF", "":
+		return false, nil
+	}
+	if len(opts) > 0 {
+		var arg OptionArg
+		for _, opt := range opts {
+			opt(&arg)
+		}
+		if arg.EmptyAsZero && strings.TrimSpace(s) == "" {
+			return false, nil
+		}
+		if arg.NumericBool {
+			if v, err := parseInt64(s); err == nil {
+				return v != 0, nil
+			}
+		}
 	}
+	return false, &ConvertError{Value: s, TargetType: "bool", Reason: ReasonParse}
 }
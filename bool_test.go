@@ -17,6 +17,7 @@
 package cast_test
 
 import (
+	"encoding/json"
 	"errors"
 	"strconv"
 	"testing"
@@ -86,6 +87,13 @@ func TestToBool(t *testing.T) {
 	assert.Equal(t, cast.ToBool(cast.StringPtr("true")), true)
 	assert.Equal(t, cast.ToBool("false"), false)
 	assert.Equal(t, cast.ToBool(cast.StringPtr("false")), false)
+	assert.Equal(t, cast.ToBool("1"), true)
+	assert.Equal(t, cast.ToBool("0"), false)
+	assert.Equal(t, cast.ToBool("yes"), true)
+	assert.Equal(t, cast.ToBool("NO"), false)
+	assert.Equal(t, cast.ToBool("on"), true)
+	assert.Equal(t, cast.ToBool("OFF"), false)
+	assert.Equal(t, cast.ToBool(""), false)
 
 	assert.Equal(t, cast.ToBool(true), true)
 	assert.Equal(t, cast.ToBool(false), false)
@@ -93,8 +101,66 @@ func TestToBool(t *testing.T) {
 	assert.Equal(t, cast.ToBool(cast.BoolPtr(false)), false)
 
 	_, err := cast.ToBoolE("abc")
-	assert.Error(t, err, "strconv.ParseBool: parsing \"abc\": invalid syntax")
+	assert.Error(t, err, "unable to cast \"abc\" to bool")
 
 	_, err = cast.ToBoolE(errors.New("abc"))
 	assert.Error(t, err, "unable to cast type \\(\\*errors\\.errorString\\) to bool")
 }
+
+func TestToBoolOr(t *testing.T) {
+	assert.Equal(t, cast.ToBoolOr(nil, true), true)
+	assert.Equal(t, cast.ToBoolOr("abc", true), true)
+	assert.Equal(t, cast.ToBoolOr("off", true), false)
+}
+
+func TestToBoolNumericBool(t *testing.T) {
+
+	_, err := cast.ToBoolE("2")
+	assert.Error(t, err, "unable to cast \"2\" to bool")
+
+	assert.Equal(t, cast.ToBool("2", cast.NumericBool()), true)
+	assert.Equal(t, cast.ToBool("-1", cast.NumericBool()), true)
+	assert.Equal(t, cast.ToBool(" 00 ", cast.NumericBool()), false)
+	assert.Equal(t, cast.ToBool("0x10", cast.NumericBool()), true)
+	assert.Equal(t, cast.ToBool("off", cast.NumericBool()), false)
+
+	_, err = cast.ToBoolE("abc", cast.NumericBool())
+	assert.Error(t, err, "unable to cast \"abc\" to bool")
+
+	v, err := cast.To[bool]("5", cast.NumericBool())
+	assert.Nil(t, err)
+	assert.True(t, v)
+}
+
+func TestToBoolBytesAndNumber(t *testing.T) {
+
+	for _, c := range []struct {
+		i any
+		v bool
+	}{
+		{[]byte("true"), true},
+		{[]byte("1"), true},
+		{[]byte("on"), true},
+		{[]byte("false"), false},
+		{[]byte("0"), false},
+		{[]byte(""), false},
+		{[]byte(nil), false},
+		{json.Number("1"), true},
+		{json.Number("-2"), true},
+		{json.Number("0.5"), true},
+		{json.Number("0"), false},
+		{json.Number("0.0"), false},
+	} {
+		v, err := cast.ToBoolE(c.i)
+		assert.Nil(t, err)
+		assert.Equal(t, v, c.v)
+	}
+
+	assert.Equal(t, cast.ToBool([]byte("2"), cast.NumericBool()), true)
+
+	_, err := cast.ToBoolE([]byte("abc"))
+	assert.Error(t, err, "unable to cast \"abc\" to bool")
+
+	_, err = cast.ToBoolE(json.Number("abc"))
+	assert.Error(t, err, "strconv.ParseFloat: parsing \"abc\": invalid syntax")
+}
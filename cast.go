@@ -1,6 +1,12 @@
 package cast
 
-import "time"
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
 
 func BoolPtr(s bool) *bool          { return &s }
 func IntPtr(s int) *int             { return &s }
@@ -18,7 +24,34 @@ func Float64Ptr(s float64) *float64 { return &s }
 func StringPtr(s string) *string    { return &s }
 
 type OptionArg struct {
-	TimeFormat string
+	TimeFormat        string
+	TimeFormats       []string
+	Location          *time.Location
+	AutoTimeUnit      bool
+	AllowFloatString  bool
+	Base              int
+	StripUnderscores  bool
+	CaseInsensitive   bool
+	FloatFormat       byte
+	FloatPrecision    int
+	Base64Bytes       bool
+	Strict            bool
+	RejectNonFinite   bool
+	TagKey            string
+	EmptyAsZero       bool
+	NumericBool       bool
+	DurationAsNanos   bool
+	TrimSpace         bool
+	NormalizeNewlines bool
+	GroupingSep       rune
+	DecimalSep        rune
+	ApplyDefaults     bool
+	Rounding          RoundingMode
+	Converter         Converter
+	ReplaceMaps       bool
+	Weak              bool
+	MatchByFieldName  bool
+	Saturate          bool
 }
 
 type Option func(arg *OptionArg)
@@ -29,70 +62,395 @@ func TimeFormat(format string) Option {
 	}
 }
 
+// TimeFormats sets the layouts tried in order when parsing a time string,
+// the first one that succeeds wins. A layout given by TimeFormat is tried
+// before them.
+func TimeFormats(layouts ...string) Option {
+	return func(arg *OptionArg) {
+		arg.TimeFormats = layouts
+	}
+}
+
+// Location sets the location in which time strings without a zone are
+// parsed and timestamps are presented. By default strings are parsed as
+// UTC and timestamps are presented in the local time.
+func Location(loc *time.Location) Option {
+	return func(arg *OptionArg) {
+		arg.Location = loc
+	}
+}
+
+// AutoTimeUnit makes timestamps without a TimeFormat unit guess their unit
+// by magnitude: about 10 digits are seconds, 13 milliseconds, 16
+// microseconds and 19 nanoseconds. An explicit TimeFormat always wins.
+func AutoTimeUnit() Option {
+	return func(arg *OptionArg) {
+		arg.AutoTimeUnit = true
+	}
+}
+
+// AllowFloatString makes integer casts accept floating-point strings
+// like "3.0" or "3e2", the value is truncated toward zero.
+func AllowFloatString() Option {
+	return func(arg *OptionArg) {
+		arg.AllowFloatString = true
+	}
+}
+
+// Base sets the base used to parse integer strings. The default base 0
+// follows the Go syntax, so "0x1F" is hexadecimal and "010" is octal,
+// while Base(10) parses both as plain decimal input.
+func Base(base int) Option {
+	return func(arg *OptionArg) {
+		arg.Base = base
+	}
+}
+
+// StripUnderscores removes underscores from integer strings before
+// parsing, so "1_000_000" is accepted whatever the base is.
+func StripUnderscores() Option {
+	return func(arg *OptionArg) {
+		arg.StripUnderscores = true
+	}
+}
+
+// FloatFormat sets the format verb and the precision used by ToStringWith
+// to render floats, see strconv.FormatFloat. The default is 'f' and -1.
+func FloatFormat(fmt byte, prec int) Option {
+	return func(arg *OptionArg) {
+		arg.FloatFormat = fmt
+		arg.FloatPrecision = prec
+	}
+}
+
+// Base64Bytes makes ToStringWith and FAST.Convert encode []byte values
+// cast to strings with base64 standard encoding, as encoding/json does,
+// instead of copying them.
+func Base64Bytes() Option {
+	return func(arg *OptionArg) {
+		arg.Base64Bytes = true
+	}
+}
+
+// Strict turns lossy conversions into errors, a float with a fractional
+// part is no longer truncated when cast to an integer. Narrowing integers
+// that overflow is an error whether Strict is given or not.
+func Strict() Option {
+	return func(arg *OptionArg) {
+		arg.Strict = true
+	}
+}
+
+// RejectNonFinite makes ToFloat64E return an error for NaN and infinite
+// values, which json and most databases can't represent.
+func RejectNonFinite() Option {
+	return func(arg *OptionArg) {
+		arg.RejectNonFinite = true
+	}
+}
+
+// TagKey names struct fields by the key tag, like `cfg:"name"`, instead of
+// the json tag, which is still used for fields without the key tag. With
+// TagKey, To converts structs and maps by FAST instead of JSON.
+func TagKey(key string) Option {
+	return func(arg *OptionArg) {
+		arg.TagKey = key
+	}
+}
+
+// MatchByFieldName makes FAST name struct fields by their Go names and
+// ignore the names in the json and TagKey tags, so that structs with
+// different tags can be copied into each other. A "-" tag still skips a
+// field. With MatchByFieldName, To converts structs and maps by FAST.
+func MatchByFieldName() Option {
+	return func(arg *OptionArg) {
+		arg.MatchByFieldName = true
+	}
+}
+
+// EmptyAsZero makes a blank string cast to the zero value of a number or
+// a bool instead of failing, like most env-var loaders do.
+func EmptyAsZero() Option {
+	return func(arg *OptionArg) {
+		arg.EmptyAsZero = true
+	}
+}
+
+// NumericBool makes ToBoolE parse a string that is not a bool token as an
+// integer, so "2" or "-1" is true. The bool tokens take precedence.
+func NumericBool() Option {
+	return func(arg *OptionArg) {
+		arg.NumericBool = true
+	}
+}
+
+// DurationAsNanos makes ToStringWith render a time.Duration as its integer
+// count of nanoseconds, like "1500000000", instead of the "1.5s" form.
+func DurationAsNanos() Option {
+	return func(arg *OptionArg) {
+		arg.DurationAsNanos = true
+	}
+}
+
+// TrimSpace makes ToStringWith remove a leading byte order mark and the
+// leading and trailing white space from its result.
+func TrimSpace() Option {
+	return func(arg *OptionArg) {
+		arg.TrimSpace = true
+	}
+}
+
+// NormalizeNewlines makes ToStringWith turn "\r\n" and lone "\r" line
+// endings of its result into "\n".
+func NormalizeNewlines() Option {
+	return func(arg *OptionArg) {
+		arg.NormalizeNewlines = true
+	}
+}
+
+// DecimalSeparators makes number casts parse localized strings, grouping
+// separators are removed and the decimal separator becomes a dot, so both
+// "1,234.56" with (',', '.') and "1.234,56" with ('.', ',') are 1234.56.
+func DecimalSeparators(grouping, decimal rune) Option {
+	return func(arg *OptionArg) {
+		arg.GroupingSep = grouping
+		arg.DecimalSep = decimal
+	}
+}
+
+// delocalize rewrites s by the DecimalSeparators option into the Go syntax.
+func delocalize(s string, arg *OptionArg) string {
+	if arg.GroupingSep == 0 && arg.DecimalSep == 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, c := range s {
+		switch c {
+		case arg.GroupingSep:
+		case arg.DecimalSep:
+			b.WriteByte('.')
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ApplyDefaults makes FAST.Convert fill the struct fields missing from a
+// source map with their `default:"..."` tags, cast like any scalar value.
+// The defaults of a nested struct apply only when its map is present.
+func ApplyDefaults() Option {
+	return func(arg *OptionArg) {
+		arg.ApplyDefaults = true
+	}
+}
+
+// RoundingMode is the way a float with a fractional part is cast to an
+// integer.
+type RoundingMode int
+
+const (
+	Truncate = RoundingMode(iota) // toward zero, the default
+	Round                         // half away from zero
+	Floor                         // toward negative infinity
+	Ceil                          // toward positive infinity
+)
+
+// Rounding sets the RoundingMode of float to integer casts, so that
+// ToInt(3.9, Rounding(Round)) is 4. A mode other than Truncate takes
+// precedence over Strict.
+func Rounding(mode RoundingMode) Option {
+	return func(arg *OptionArg) {
+		arg.Rounding = mode
+	}
+}
+
+// WithConverter makes To convert structs, maps and slices by c instead of
+// JSON, or FAST when TagKey is given. The options of To aren't passed to
+// c, a converter that needs some binds them itself, like
+// ConverterFunc(func(src, dest any) error { return FAST.Convert(src, dest, TagKey("cfg")) }).
+func WithConverter(c Converter) Option {
+	return func(arg *OptionArg) {
+		arg.Converter = c
+	}
+}
+
+// ReplaceMaps makes FAST.Convert replace a non-nil map in dest by a new
+// map, instead of merging the keys of src into it. The map of the caller
+// is left untouched.
+func ReplaceMaps() Option {
+	return func(arg *OptionArg) {
+		arg.ReplaceMaps = true
+	}
+}
+
+// Weak makes To cast to named scalar types, like `type Port int` or
+// `type Name string`, by the cast of their underlying kind, so that
+// To[Port]("8080") works instead of failing in JSON. With Weak, ToInt64E
+// and the casts built on it read a string that is neither an integer nor,
+// with AllowFloatString, a float as a bool token of ToBoolE, so "true" is
+// 1 and "false" is 0. Without Weak such strings are errors.
+func Weak() Option {
+	return func(arg *OptionArg) {
+		arg.Weak = true
+	}
+}
+
+// emptyAsZero reports whether the EmptyAsZero option is given.
+func emptyAsZero(opts []Option) bool {
+	if len(opts) == 0 {
+		return false
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	return arg.EmptyAsZero
+}
+
+// Saturate makes the narrowing casts, like ToInt8E or ToUint16E, clamp a
+// value that doesn't fit to the minimum or maximum of the type instead of
+// returning an error, so ToInt8(300, Saturate()) is 127. It also makes
+// the unsigned casts, ToUint64E included, clamp a negative value to 0.
+func Saturate() Option {
+	return func(arg *OptionArg) {
+		arg.Saturate = true
+	}
+}
+
+// saturate reports whether the Saturate option is given.
+func saturate(opts []Option) bool {
+	if len(opts) == 0 {
+		return false
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	return arg.Saturate
+}
+
+// CaseInsensitive makes FAST.Convert fall back to a case-insensitive
+// match when no struct field has the exact name of a source key.
+func CaseInsensitive() Option {
+	return func(arg *OptionArg) {
+		arg.CaseInsensitive = true
+	}
+}
+
 // To 将 i 转换为 T 类型的值。
 func To[T any](i interface{}, opts ...Option) (T, error) {
 	var t T
-	if err := to(i, &t); err != nil {
+	if err := to(i, &t, opts...); err != nil {
 		return t, err
 	}
 	return t, nil
 }
 
+// ToSlice 将 i 转换为 []T 类型的值，i 为 slice 或 array 时逐个元素转换，
+// 否则作为单个元素转换。
+func ToSlice[T any](i any, opts ...Option) ([]T, error) {
+	if i == nil {
+		return []T{}, nil
+	}
+	v := reflect.ValueOf(i)
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		var t T
+		if err := to(i, &t, opts...); err != nil {
+			return nil, err
+		}
+		return []T{t}, nil
+	}
+	r := make([]T, v.Len())
+	for j := range r {
+		if err := to(v.Index(j).Interface(), &r[j], opts...); err != nil {
+			return nil, fmt.Errorf("unable to cast element %d: %w", j, err)
+		}
+	}
+	return r, nil
+}
+
+// ToMap 将 i 转换为 map[K]V 类型的值，i 为 map 或 struct，键和值分别按照
+// To 的规则转换，struct 的键为 json tag 指定的字段名。任何一个键或值转换
+// 失败都会返回错误，不会返回部分结果。
+func ToMap[K comparable, V any](i any, opts ...Option) (map[K]V, error) {
+	if i == nil {
+		return map[K]V{}, nil
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		m, err := ToStringMapE(i)
+		if err != nil {
+			return nil, err
+		}
+		v = reflect.ValueOf(m)
+	}
+	if v.Kind() != reflect.Map {
+		return nil, unsupportedTypeError(i, "map")
+	}
+	r := make(map[K]V, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var (
+			key K
+			val V
+		)
+		if err := to(iter.Key().Interface(), &key, opts...); err != nil {
+			return nil, fmt.Errorf("unable to cast key %v: %w", iter.Key(), err)
+		}
+		if err := to(iter.Value().Interface(), &val, opts...); err != nil {
+			return nil, fmt.Errorf("unable to cast value of key %v: %w", iter.Key(), err)
+		}
+		r[key] = val
+	}
+	return r, nil
+}
+
 func to(i any, v any, opts ...Option) error {
 	var err error
 	switch p := v.(type) {
 	case *bool:
-		*p, err = ToBoolE(i)
+		*p, err = ToBoolE(i, opts...)
 	case *int:
-		var r int64
-		r, err = ToInt64E(i)
-		*p = int(r)
+		*p, err = ToIntE(i, opts...)
 	case *int8:
-		var r int64
-		r, err = ToInt64E(i)
-		*p = int8(r)
+		*p, err = ToInt8E(i, opts...)
 	case *int16:
-		var r int64
-		r, err = ToInt64E(i)
-		*p = int16(r)
+		*p, err = ToInt16E(i, opts...)
 	case *int32:
-		var r int64
-		r, err = ToInt64E(i)
-		*p = int32(r)
+		*p, err = ToInt32E(i, opts...)
 	case *int64:
-		var r int64
-		r, err = ToInt64E(i)
-		*p = r
+		*p, err = ToInt64E(i, opts...)
 	case *uint:
-		var r uint64
-		r, err = ToUint64E(i)
-		*p = uint(r)
+		*p, err = ToUintE(i, opts...)
 	case *uint8:
-		var r uint64
-		r, err = ToUint64E(i)
-		*p = uint8(r)
+		*p, err = ToUint8E(i, opts...)
 	case *uint16:
-		var r uint64
-		r, err = ToUint64E(i)
-		*p = uint16(r)
+		*p, err = ToUint16E(i, opts...)
 	case *uint32:
-		var r uint64
-		r, err = ToUint64E(i)
-		*p = uint32(r)
+		*p, err = ToUint32E(i, opts...)
 	case *uint64:
-		var r uint64
-		r, err = ToUint64E(i)
-		*p = r
+		*p, err = ToUint64E(i, opts...)
 	case *float32:
-		var r float64
-		r, err = ToFloat64E(i)
-		*p = float32(r)
+		*p, err = ToFloat32E(i, opts...)
 	case *float64:
 		var r float64
-		r, err = ToFloat64E(i)
+		r, err = ToFloat64E(i, opts...)
 		*p = r
+	case *complex64:
+		*p, err = ToComplex64E(i)
+	case *complex128:
+		*p, err = ToComplex128E(i)
+	case **big.Int:
+		*p, err = ToBigIntE(i)
+	case **big.Float:
+		*p, err = ToBigFloatE(i)
 	case *string:
-		*p = ToString(i)
+		*p = ToStringWith(i, opts...)
 		err = nil
 	case *time.Duration:
 		var r time.Duration
@@ -103,7 +461,121 @@ func to(i any, v any, opts ...Option) error {
 		r, err = ToTimeE(i, opts...)
 		*p = r
 	default:
+		if p := reflect.ValueOf(v).Elem(); p.Kind() == reflect.Pointer {
+			return toPointer(i, p, opts...)
+		}
+		if len(opts) > 0 {
+			var arg OptionArg
+			for _, opt := range opts {
+				opt(&arg)
+			}
+			if arg.Weak {
+				if ok, err := toWeak(i, reflect.ValueOf(v).Elem(), opts...); ok {
+					return err
+				}
+			}
+			if arg.Converter != nil {
+				return arg.Converter.Convert(i, v)
+			}
+			if arg.TagKey != "" || arg.MatchByFieldName {
+				return FAST.Convert(i, v, opts...)
+			}
+		}
 		return JSON.Convert(i, v)
 	}
 	return err
 }
+
+// toWeak sets p, which is of a named scalar type, to i cast by the cast
+// of its kind. It returns false when the kind of p is not a scalar.
+func toWeak(i any, p reflect.Value, opts ...Option) (bool, error) {
+	switch p.Kind() {
+	case reflect.Bool:
+		b, err := ToBoolE(i, opts...)
+		if err != nil {
+			return true, err
+		}
+		p.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := ToInt64E(i, opts...)
+		if err != nil {
+			return true, retarget(err, p.Kind().String())
+		}
+		if n, err = narrowInt(n, bitSize(p), opts); err != nil {
+			return true, err
+		}
+		p.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := ToUint64E(i, opts...)
+		if err != nil {
+			return true, retarget(err, p.Kind().String())
+		}
+		if n, err = narrowUint(n, bitSize(p), opts); err != nil {
+			return true, err
+		}
+		p.SetUint(n)
+	case reflect.Float32:
+		f, err := ToFloat32E(i, opts...)
+		if err != nil {
+			return true, err
+		}
+		p.SetFloat(float64(f))
+	case reflect.Float64:
+		f, err := ToFloat64E(i, opts...)
+		if err != nil {
+			return true, err
+		}
+		p.SetFloat(f)
+	case reflect.String:
+		p.SetString(ToStringWith(i, opts...))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// bitSize returns the size of the integer p in bits, 0 for int and uint.
+func bitSize(p reflect.Value) int {
+	switch p.Kind() {
+	case reflect.Int, reflect.Uint:
+		return 0
+	}
+	return p.Type().Bits()
+}
+
+// basicOf returns i as a value of its basic kind when i is of a named type
+// like `type ID int64` or a pointer to one, or the string form of i when
+// it is a fmt.Stringer. It returns false when neither applies.
+func basicOf(i any) (any, bool) {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return basicValue(v), true
+	}
+	if s, ok := i.(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return nil, false
+}
+
+// toPointer allocates the value that p points to and fills it by i, p is
+// left nil when i is nil.
+func toPointer(i any, p reflect.Value, opts ...Option) error {
+	if iv := reflect.ValueOf(i); !iv.IsValid() || (iv.Kind() == reflect.Pointer && iv.IsNil()) {
+		p.Set(reflect.Zero(p.Type()))
+		return nil
+	}
+	e := reflect.New(p.Type().Elem())
+	if err := to(i, e.Interface(), opts...); err != nil {
+		return err
+	}
+	p.Set(e)
+	return nil
+}
@@ -1 +1,246 @@
 package cast_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToSlice(t *testing.T) {
+
+	v1, err := cast.ToSlice[int]([]string{"1", "2"})
+	assert.Nil(t, err)
+	assert.Equal(t, v1, []int{1, 2})
+
+	v2, err := cast.ToSlice[string]([2]interface{}{1, true})
+	assert.Nil(t, err)
+	assert.Equal(t, v2, []string{"1", "true"})
+
+	v3, err := cast.ToSlice[float64]("1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, v3, []float64{1.5})
+
+	v4, err := cast.ToSlice[time.Duration]([]int{1, 2}, cast.TimeFormat("s"))
+	assert.Nil(t, err)
+	assert.Equal(t, v4, []time.Duration{time.Second, 2 * time.Second})
+
+	v5, err := cast.ToSlice[int](nil)
+	assert.Nil(t, err)
+	assert.Equal(t, v5, []int{})
+
+	_, err = cast.ToSlice[int8]([]int{1, 300})
+	assert.Error(t, err, "unable to cast element 1: value 300 overflows int8")
+}
+
+func TestToMap(t *testing.T) {
+
+	v1, err := cast.ToMap[string, int](map[string]string{"a": "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, v1, map[string]int{"a": 1})
+
+	v2, err := cast.ToMap[int, bool](map[string]interface{}{"1": "yes", "2": 0})
+	assert.Nil(t, err)
+	assert.Equal(t, v2, map[int]bool{1: true, 2: false})
+
+	type Stu struct {
+		Name string `json:"name"`
+		Age  string `json:"age"`
+	}
+	v3, err := cast.ToMap[string, string](&Stu{Name: "a", Age: "3"})
+	assert.Nil(t, err)
+	assert.Equal(t, v3, map[string]string{"name": "a", "age": "3"})
+
+	v4, err := cast.ToMap[string, int](nil)
+	assert.Nil(t, err)
+	assert.Equal(t, v4, map[string]int{})
+
+	_, err = cast.ToMap[int, int](map[string]int{"a": 1})
+	assert.Error(t, err, "unable to cast key a: strconv.ParseInt: parsing \"a\": invalid syntax")
+
+	_, err = cast.ToMap[string, int](map[string]string{"a": "b"})
+	assert.Error(t, err, "unable to cast value of key a: strconv.ParseInt: parsing \"b\": invalid syntax")
+
+	_, err = cast.ToMap[string, int]([]int{1})
+	assert.Error(t, err, "unable to cast type \\(\\[\\]int\\) to map")
+}
+
+func TestToPointer(t *testing.T) {
+
+	v1, err := cast.To[*int]("5")
+	assert.Nil(t, err)
+	assert.Equal(t, *v1, 5)
+
+	v2, err := cast.To[*time.Time]("2023-01-01 08:00:00 +0800")
+	assert.Nil(t, err)
+	assert.True(t, v2.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	v3, err := cast.To[*string](nil)
+	assert.Nil(t, err)
+	assert.Nil(t, v3)
+
+	v4, err := cast.To[*int]((*string)(nil))
+	assert.Nil(t, err)
+	assert.Nil(t, v4)
+
+	type Stu struct {
+		Name string `json:"name"`
+	}
+	v5, err := cast.To[*Stu](map[string]string{"name": "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, v5, &Stu{Name: "a"})
+
+	_, err = cast.To[*int]("abc")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	v6, err := cast.To[**int]("5")
+	assert.Nil(t, err)
+	assert.Equal(t, **v6, 5)
+
+	v7, err := cast.To[**int](nil)
+	assert.Nil(t, err)
+	assert.Nil(t, v7)
+
+	_, err = cast.To[**int]("abc")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+}
+
+func TestEmptyAsZero(t *testing.T) {
+
+	_, err := cast.ToInt64E("")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"\": invalid syntax")
+	_, err = cast.ToUint64E(" ")
+	assert.Error(t, err, "strconv.ParseUint: parsing \"\": invalid syntax")
+	_, err = cast.ToFloat64E("\t")
+	assert.Error(t, err, "strconv.ParseFloat: parsing \"\": invalid syntax")
+	_, err = cast.ToBoolE("  ")
+	assert.Error(t, err, "unable to cast \"  \" to bool")
+
+	i, err := cast.ToInt64E("", cast.EmptyAsZero())
+	assert.Nil(t, err)
+	assert.Equal(t, i, int64(0))
+
+	u, err := cast.ToUint64E(" ", cast.EmptyAsZero())
+	assert.Nil(t, err)
+	assert.Equal(t, u, uint64(0))
+
+	f, err := cast.ToFloat64E(cast.StringPtr("\t"), cast.EmptyAsZero())
+	assert.Nil(t, err)
+	assert.Equal(t, f, float64(0))
+
+	b, err := cast.ToBoolE("  ", cast.EmptyAsZero())
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	n, err := cast.To[int8]("", cast.EmptyAsZero())
+	assert.Nil(t, err)
+	assert.Equal(t, n, int8(0))
+
+	_, err = cast.ToIntE("x", cast.EmptyAsZero())
+	assert.Error(t, err, "strconv.ParseInt: parsing \"x\": invalid syntax")
+}
+
+func TestWithConverter(t *testing.T) {
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	src := map[string]interface{}{"Name": "a"}
+
+	u, err := cast.To[User](src)
+	assert.Nil(t, err)
+	assert.Equal(t, u, User{Name: "a"})
+
+	fast := cast.ConverterFunc(func(src, dest any) error {
+		return cast.FAST.Convert(src, dest)
+	})
+	u, err = cast.To[User](src, cast.WithConverter(fast))
+	assert.Nil(t, err)
+	assert.Equal(t, u, User{})
+
+	fold := cast.ConverterFunc(func(src, dest any) error {
+		return cast.FAST.Convert(src, dest, cast.CaseInsensitive())
+	})
+	u, err = cast.To[User](src, cast.WithConverter(fold))
+	assert.Nil(t, err)
+	assert.Equal(t, u, User{Name: "a"})
+
+	called := false
+	c := cast.ConverterFunc(func(src, dest any) error {
+		called = true
+		return cast.JSON.Convert(src, dest)
+	})
+	u, err = cast.To[User](src, cast.WithConverter(c))
+	assert.Nil(t, err)
+	assert.True(t, called)
+	assert.Equal(t, u, User{Name: "a"})
+
+	_, err = cast.To[User](src, cast.WithConverter(cast.ConverterFunc(func(src, dest any) error {
+		return errors.New("failed")
+	})))
+	assert.Error(t, err, "failed")
+
+	var cs []cast.Converter
+	cs = append(cs, cast.JSON, fast, c)
+	for _, c := range cs {
+		var r User
+		assert.Nil(t, c.Convert(map[string]interface{}{"name": "b"}, &r))
+		assert.Equal(t, r, User{Name: "b"})
+	}
+}
+
+func TestWeak(t *testing.T) {
+
+	type Port uint16
+	type Name string
+	type Ratio float32
+	type Enabled bool
+	type Level int8
+
+	_, err := cast.To[Port]("8080")
+	assert.Error(t, err, "cannot unmarshal")
+
+	p, err := cast.To[Port]("8080", cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, p, Port(8080))
+
+	n, err := cast.To[Name](42, cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, n, Name("42"))
+
+	r, err := cast.To[Ratio]("0.5", cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, r, Ratio(0.5))
+
+	e, err := cast.To[Enabled]("yes", cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, e, Enabled(true))
+
+	l, err := cast.To[Level](3.6, cast.Weak(), cast.Rounding(cast.Round))
+	assert.Nil(t, err)
+	assert.Equal(t, l, Level(4))
+
+	pp, err := cast.To[*Port]("80", cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, *pp, Port(80))
+
+	ps, err := cast.ToSlice[Port]([]string{"1", "2"}, cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, ps, []Port{1, 2})
+
+	_, err = cast.To[Port]("70000", cast.Weak())
+	assert.Error(t, err, "value 70000 overflows uint16")
+
+	_, err = cast.To[Level]("abc", cast.Weak())
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	type User struct {
+		Name string `json:"name"`
+	}
+	u, err := cast.To[User](map[string]interface{}{"name": "a"}, cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, u, User{Name: "a"})
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ToComplex64 casts an any to a complex64.
+// When type is clear, it is recommended to use standard library functions.
+func ToComplex64(i any) complex64 {
+	v, _ := ToComplex64E(i)
+	return v
+}
+
+// ToComplex64E casts an any to a complex64, it returns an error when a
+// finite part is out of the range of float32.
+// When type is clear, it is recommended to use standard library functions.
+func ToComplex64E(i any) (complex64, error) {
+	switch s := i.(type) {
+	case string:
+		v, err := parseComplex(s, 64)
+		return complex64(v), err
+	case *string:
+		v, err := parseComplex(*s, 64)
+		return complex64(v), err
+	}
+	v, err := ToComplex128E(i)
+	if err != nil {
+		return 0, retarget(err, "complex64")
+	}
+	if overflowsFloat32(real(v)) || overflowsFloat32(imag(v)) {
+		return 0, overflowError(v, "complex64")
+	}
+	return complex64(v), nil
+}
+
+// overflowsFloat32 reports whether the finite f is out of the range of
+// float32.
+func overflowsFloat32(f float64) bool {
+	return !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32
+}
+
+// ToComplex128 casts an any to a complex128.
+// When type is clear, it is recommended to use standard library functions.
+func ToComplex128(i any) complex128 {
+	v, _ := ToComplex128E(i)
+	return v
+}
+
+// ToComplex128E casts an any to a complex128. A real number r is cast to
+// complex(r, 0) and a string is parsed by strconv.ParseComplex.
+// When type is clear, it is recommended to use standard library functions.
+func ToComplex128E(i any) (complex128, error) {
+	switch s := i.(type) {
+	case nil:
+		return 0, nil
+	case complex64:
+		return complex128(s), nil
+	case complex128:
+		return s, nil
+	case *complex64:
+		return complex128(*s), nil
+	case *complex128:
+		return *s, nil
+	case string:
+		return parseComplex(s, 128)
+	case *string:
+		return parseComplex(*s, 128)
+	default:
+		v, err := ToFloat64E(i)
+		if err != nil {
+			return 0, retarget(err, "complex128")
+		}
+		return complex(v, 0), nil
+	}
+}
+
+func parseComplex(s string, bitSize int) (complex128, error) {
+	v, err := strconv.ParseComplex(strings.TrimSpace(s), bitSize)
+	if err != nil {
+		return v, parseError(s, "complex"+strconv.Itoa(bitSize), err)
+	}
+	return v, nil
+}
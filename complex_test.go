@@ -0,0 +1,72 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToComplex128(t *testing.T) {
+
+	assert.Equal(t, cast.ToComplex128(nil), complex128(0))
+
+	assert.Equal(t, cast.ToComplex128(3), complex(3, 0))
+	assert.Equal(t, cast.ToComplex128(uint8(3)), complex(3, 0))
+	assert.Equal(t, cast.ToComplex128(1.5), complex(1.5, 0))
+	assert.Equal(t, cast.ToComplex128(cast.Float64Ptr(1.5)), complex(1.5, 0))
+	assert.Equal(t, cast.ToComplex128(complex64(1+2i)), complex(1, 2))
+	assert.Equal(t, cast.ToComplex128(1+2i), complex(1, 2))
+	assert.Equal(t, cast.ToComplex128(" 1+2i "), complex(1, 2))
+	assert.Equal(t, cast.ToComplex128(cast.StringPtr("(3-4i)")), complex(3, -4))
+
+	_, err := cast.ToComplex128E("abc")
+	assert.Error(t, err, "strconv.ParseComplex: parsing \"abc\": invalid syntax")
+	_, err = cast.ToComplex128E([]int{1})
+	assert.Error(t, err, "unable to cast type \\(\\[\\]int\\) to complex128")
+
+	v, err := cast.To[complex128]("1+2i")
+	assert.Nil(t, err)
+	assert.Equal(t, v, complex(1, 2))
+}
+
+func TestToComplex64(t *testing.T) {
+
+	assert.Equal(t, cast.ToComplex64(nil), complex64(0))
+
+	assert.Equal(t, cast.ToComplex64(3), complex64(complex(3, 0)))
+	assert.Equal(t, cast.ToComplex64(1+2i), complex64(complex(1, 2)))
+	assert.Equal(t, cast.ToComplex64("1.5i"), complex64(complex(0, 1.5)))
+
+	_, err := cast.ToComplex64E("abc")
+	assert.Error(t, err, "strconv.ParseComplex: parsing \"abc\": invalid syntax")
+	_, err = cast.ToComplex64E(struct{}{})
+	assert.Error(t, err, "unable to cast type \\(struct {}\\) to complex64")
+
+	_, err = cast.ToComplex64E(1e300)
+	assert.Error(t, err, "value \\(1e\\+300\\+0i\\) overflows complex64")
+	_, err = cast.ToComplex64E(complex(1, -1e300))
+	assert.Error(t, err, "value \\(1-1e\\+300i\\) overflows complex64")
+	assert.Equal(t, cast.ToComplex64(math.Inf(1)), complex64(complex(math.Inf(1), 0)))
+
+	v, err := cast.To[complex64](3)
+	assert.Nil(t, err)
+	assert.Equal(t, v, complex64(complex(3, 0)))
+}
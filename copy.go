@@ -0,0 +1,27 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+// DeepCopy returns a copy of src that shares no memory with it, maps,
+// slices and pointers are all cloned, even a pointer that src refers to
+// more than once. It is built on FAST, so only the fields FAST.Convert
+// sees are copied, and a cyclic src is an error.
+func DeepCopy[T any](src T) (T, error) {
+	var dest T
+	err := FAST.Convert(src, &dest)
+	return dest, err
+}
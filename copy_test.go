@@ -0,0 +1,73 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestDeepCopy(t *testing.T) {
+
+	type Node struct {
+		Name     string           `json:"name"`
+		Tags     []string         `json:"tags"`
+		Attrs    map[string]int   `json:"attrs"`
+		Child    *Node            `json:"child"`
+		Children map[string]*Node `json:"children"`
+		At       time.Time        `json:"at"`
+	}
+
+	shared := &Node{Name: "shared"}
+	src := &Node{
+		Name:     "root",
+		Tags:     []string{"a"},
+		Attrs:    map[string]int{"x": 1},
+		Child:    shared,
+		Children: map[string]*Node{"s": shared},
+		At:       time.Unix(1, 0),
+	}
+
+	dest, err := cast.DeepCopy(src)
+	assert.Nil(t, err)
+	assert.Equal(t, dest, src)
+
+	dest.Tags[0] = "b"
+	dest.Attrs["x"] = 2
+	dest.Child.Name = "changed"
+	assert.Equal(t, src.Tags, []string{"a"})
+	assert.Equal(t, src.Attrs, map[string]int{"x": 1})
+	assert.Equal(t, shared.Name, "shared")
+	assert.False(t, dest.Child == dest.Children["s"])
+
+	m, err := cast.DeepCopy(map[string]any{"a": []any{1, map[string]any{"b": 2}}})
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]any{"a": []any{1, map[string]any{"b": 2}}})
+
+	var nilNode *Node
+	n, err := cast.DeepCopy(nilNode)
+	assert.Nil(t, err)
+	assert.True(t, n == nil)
+
+	cyclic := &Node{Name: "cyclic"}
+	cyclic.Child = cyclic
+	_, err = cast.DeepCopy(cyclic)
+	assert.Error(t, err, "encountered a cycle")
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Decoder decodes values into a T. It resolves once how T is decoded, so
+// it suits decoding many values, like the rows of a table, into the same
+// type. A Decoder is safe for concurrent use.
+type Decoder[T any] struct {
+	opts []Option
+	fast bool
+
+	// The options applied once, and the fields of a struct T.
+	arg    OptionArg
+	typ    reflect.Type
+	fields structFields
+}
+
+// NewDecoder returns a Decoder for T with the given options. Structs,
+// maps, slices and arrays are decoded by FAST, with the options applied
+// and the fields of a struct T resolved in advance and reused by every
+// Decode. The other types are decoded like To does.
+func NewDecoder[T any](opts ...Option) *Decoder[T] {
+	d := &Decoder[T]{opts: opts}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		d.fast = true
+	case reflect.Struct:
+		if t == timeType {
+			break
+		}
+		d.fast = true
+		d.typ = t
+	}
+	if !d.fast {
+		return d
+	}
+	for _, opt := range opts {
+		opt(&d.arg)
+	}
+	if d.typ != nil {
+		d.fields = cachedArgFields(d.typ, &d.arg)
+	}
+	return d
+}
+
+// Decode decodes src into a T.
+func (d *Decoder[T]) Decode(src any) (T, error) {
+	if !d.fast {
+		return To[T](src, d.opts...)
+	}
+	var t T
+	l := newMiddleValueList()
+	defer middleValueListPool.Put(l)
+	l.arg = d.arg
+	if d.typ != nil {
+		l.planType = d.typ
+		l.planFields = &d.fields
+	}
+	err := l.convert(src, &t, false, nil)
+	return t, err
+}
+
+// ConvertEach converts the elements of src one by one into a D by FAST and
+// passes each of them to fn, so that only one element is converted at a
+// time, and a single buffer serves all of them. It stops at the first
+// error, either of a conversion or returned by fn.
+func ConvertEach[S, D any](src []S, fn func(D) error, opts ...Option) error {
+	c := NewFastConverter()
+	for i := range src {
+		var d D
+		if err := c.Convert(src[i], &d, opts...); err != nil {
+			return fmt.Errorf("unable to convert element %d: %w", i, err)
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestDecoder(t *testing.T) {
+
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	dec := cast.NewDecoder[Row](cast.TagKey("db"))
+	for i, m := range []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": "2", "name": "b"},
+	} {
+		r, err := dec.Decode(m)
+		assert.Nil(t, err)
+		assert.Equal(t, r, Row{ID: int64(i + 1), Name: string(rune('a' + i))})
+	}
+
+	_, err := dec.Decode(map[string]interface{}{"id": "abc"})
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	rows, err := cast.NewDecoder[[]Row](cast.TagKey("db")).Decode([]interface{}{
+		map[string]interface{}{"id": 1},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, rows, []Row{{ID: 1}})
+
+	n, err := cast.NewDecoder[int]().Decode("3")
+	assert.Nil(t, err)
+	assert.Equal(t, n, 3)
+
+	tm, err := cast.NewDecoder[time.Time](cast.TimeFormat("s")).Decode(3)
+	assert.Nil(t, err)
+	assert.Equal(t, tm, time.Unix(3, 0))
+
+	type Node struct {
+		Name string `json:"name"`
+		Port uint16 `json:"port" default:"80"`
+		Next *Node  `json:"next"`
+	}
+	nd := cast.NewDecoder[Node](cast.CaseInsensitive(), cast.ApplyDefaults())
+	for i := 0; i < 2; i++ {
+		v, err := nd.Decode(map[string]any{"NAME": "a", "next": map[string]any{"Name": "b", "port": 8}})
+		assert.Nil(t, err)
+		assert.Equal(t, v, Node{Name: "a", Port: 80, Next: &Node{Name: "b", Port: 8}})
+	}
+
+	// A plain FAST.Convert afterwards isn't affected by the plan.
+	var v Node
+	err = cast.FAST.Convert(map[string]any{"NAME": "a"}, &v)
+	assert.Nil(t, err)
+	assert.Equal(t, v, Node{})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+
+	type Row struct {
+		ID    int64   `json:"id"`
+		Name  string  `json:"name"`
+		Score float64 `json:"score"`
+	}
+
+	src := map[string]interface{}{"id": 1, "name": "a", "score": 1.5}
+
+	b.Run("To", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = cast.To[Row](src)
+		}
+	})
+
+	b.Run("Decoder", func(b *testing.B) {
+		dec := cast.NewDecoder[Row]()
+		for i := 0; i < b.N; i++ {
+			_, _ = dec.Decode(src)
+		}
+	})
+}
+
+func TestConvertEach(t *testing.T) {
+
+	type Src struct {
+		ID   string `json:"id"`
+		Tags []int  `json:"tags"`
+	}
+	type Dest struct {
+		ID   int64    `json:"id"`
+		Tags []string `json:"tags"`
+	}
+
+	src := []Src{{ID: "1", Tags: []int{1}}, {ID: "2"}, {ID: "3", Tags: []int{3, 4}}}
+
+	var r []Dest
+	err := cast.ConvertEach(src, func(d Dest) error {
+		r = append(r, d)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, r, []Dest{{ID: 1, Tags: []string{"1"}}, {ID: 2}, {ID: 3, Tags: []string{"3", "4"}}})
+
+	n := 0
+	err = cast.ConvertEach(src, func(d Dest) error {
+		if n++; d.ID == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	assert.Error(t, err, "stop")
+	assert.Equal(t, n, 2)
+
+	err = cast.ConvertEach([]Src{{ID: "1"}, {ID: "x"}}, func(d Dest) error { return nil })
+	assert.Error(t, err, "unable to convert element 1: strconv.ParseInt: parsing \"x\": invalid syntax")
+
+	var m []map[string]interface{}
+	err = cast.ConvertEach([]Src{{ID: "1"}}, func(d map[string]interface{}) error {
+		m = append(m, d)
+		return nil
+	}, cast.TagKey("json"))
+	assert.Nil(t, err)
+	assert.Equal(t, m, []map[string]interface{}{{"id": "1", "tags": nil}})
+}
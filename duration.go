@@ -18,6 +18,9 @@ package cast
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -37,62 +40,81 @@ func ToDuration(i any, opts ...Option) time.Duration {
 	return v
 }
 
-// ToDurationE casts an any to a time.Duration.
+// ToDurationOr casts an any to a time.Duration, it returns def when i is
+// nil or the cast fails.
+func ToDurationOr(i any, def time.Duration, opts ...Option) time.Duration {
+	if i == nil {
+		return def
+	}
+	v, err := ToDurationE(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ToDurationE casts an any to a time.Duration. Numbers are counted in the
+// unit given by TimeFormat, nanoseconds by default. Strings are parsed by
+// time.ParseDuration, extended with the fixed units "d" for 24h and "w"
+// for 168h as in "1w2d3h", and a bare number like "1.5" is accepted too
+// when TimeFormat gives the unit.
 // When type is clear, it is recommended to use standard library functions.
 func ToDurationE(i any, opts ...Option) (time.Duration, error) {
-	base := int64(time.Nanosecond)
+	base, unit := int64(time.Nanosecond), "ns"
+	hasUnit := false
 	if len(opts) > 0 {
-		arg := OptionArg{
-			TimeFormat: "ns",
-		}
+		var arg OptionArg
 		for _, opt := range opts {
 			opt(&arg)
 		}
-		base, _ = unitMap[arg.TimeFormat]
+		if arg.TimeFormat != "" {
+			base, hasUnit = unitMap[arg.TimeFormat]
+			unit = arg.TimeFormat
+		}
 	}
 	switch s := i.(type) {
 	case nil:
 		return 0, nil
 	case int:
-		return time.Duration(int64(s) * base), nil
+		return intDuration(int64(s), base, unit)
 	case int8:
-		return time.Duration(int64(s) * base), nil
+		return intDuration(int64(s), base, unit)
 	case int16:
-		return time.Duration(int64(s) * base), nil
+		return intDuration(int64(s), base, unit)
 	case int32:
-		return time.Duration(int64(s) * base), nil
+		return intDuration(int64(s), base, unit)
 	case int64:
-		return time.Duration(int64(s) * base), nil
+		return intDuration(int64(s), base, unit)
 	case *int:
-		return time.Duration(int64(*s) * base), nil
+		return intDuration(int64(*s), base, unit)
 	case *int8:
-		return time.Duration(int64(*s) * base), nil
+		return intDuration(int64(*s), base, unit)
 	case *int16:
-		return time.Duration(int64(*s) * base), nil
+		return intDuration(int64(*s), base, unit)
 	case *int32:
-		return time.Duration(int64(*s) * base), nil
+		return intDuration(int64(*s), base, unit)
 	case *int64:
-		return time.Duration(int64(*s) * base), nil
+		return intDuration(int64(*s), base, unit)
 	case uint:
-		return time.Duration(int64(s) * base), nil
+		return uintDuration(uint64(s), base, unit)
 	case uint8:
-		return time.Duration(int64(s) * base), nil
+		return uintDuration(uint64(s), base, unit)
 	case uint16:
-		return time.Duration(int64(s) * base), nil
+		return uintDuration(uint64(s), base, unit)
 	case uint32:
-		return time.Duration(int64(s) * base), nil
+		return uintDuration(uint64(s), base, unit)
 	case uint64:
-		return time.Duration(int64(s) * base), nil
+		return uintDuration(uint64(s), base, unit)
 	case *uint:
-		return time.Duration(int64(*s) * base), nil
+		return uintDuration(uint64(*s), base, unit)
 	case *uint8:
-		return time.Duration(int64(*s) * base), nil
+		return uintDuration(uint64(*s), base, unit)
 	case *uint16:
-		return time.Duration(int64(*s) * base), nil
+		return uintDuration(uint64(*s), base, unit)
 	case *uint32:
-		return time.Duration(int64(*s) * base), nil
+		return uintDuration(uint64(*s), base, unit)
 	case *uint64:
-		return time.Duration(int64(*s) * base), nil
+		return uintDuration(uint64(*s), base, unit)
 	case float32:
 		return time.Duration(float64(s) * float64(base)), nil
 	case float64:
@@ -102,12 +124,111 @@ func ToDurationE(i any, opts ...Option) (time.Duration, error) {
 	case *float64:
 		return time.Duration((*s) * float64(base)), nil
 	case string:
-		return time.ParseDuration(s)
+		return parseDuration(s, base, hasUnit)
 	case *string:
-		return time.ParseDuration(*s)
+		return parseDuration(*s, base, hasUnit)
 	case time.Duration:
 		return s, nil
 	default:
-		return 0, fmt.Errorf("unable to cast type (%T) to time.Duration", i)
+		return 0, unsupportedTypeError(i, "time.Duration")
+	}
+}
+
+func intDuration(v int64, base int64, unit string) (time.Duration, error) {
+	if base != 0 && (v > math.MaxInt64/base || v < math.MinInt64/base) {
+		return 0, durationOverflow(v, unit)
+	}
+	return time.Duration(v * base), nil
+}
+
+func uintDuration(v uint64, base int64, unit string) (time.Duration, error) {
+	if v > math.MaxInt64 {
+		return 0, durationOverflow(v, unit)
+	}
+	return intDuration(int64(v), base, unit)
+}
+
+func durationOverflow[T int64 | uint64](v T, unit string) error {
+	return &ConvertError{
+		Value:      v,
+		TargetType: "time.Duration",
+		Reason:     ReasonOverflow,
+		Err:        fmt.Errorf("duration %d%s overflows", v, unit),
+	}
+}
+
+func parseDuration(s string, base int64, hasUnit bool) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil && strings.ContainsAny(s, "dw") {
+		if v, ok := parseDayDuration(s); ok {
+			return v, nil
+		}
+	}
+	if err == nil {
+		return d, nil
+	}
+	if hasUnit {
+		if f, e := strconv.ParseFloat(strings.TrimSpace(s), 64); e == nil {
+			return time.Duration(f * float64(base)), nil
+		}
+	}
+	return d, parseError(s, "time.Duration", err)
+}
+
+// parseDayDuration parses a duration with days "d" and weeks "w", like
+// "1w2d3h". They are fixed 24h and 168h spans that know nothing of the
+// calendar, and the other units are left to time.ParseDuration.
+func parseDayDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var (
+		days float64
+		rest strings.Builder
+	)
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+			i++
+		}
+		j := i
+		for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+		if i == 0 || i == j {
+			return 0, false
+		}
+		switch s[i:j] {
+		case "d", "w":
+			f, err := strconv.ParseFloat(s[:i], 64)
+			if err != nil {
+				return 0, false
+			}
+			if s[i] == 'w' {
+				f *= 7
+			}
+			days += f
+		default:
+			rest.WriteString(s[:j])
+		}
+		s = s[j:]
+	}
+	if days*float64(24*time.Hour) >= math.MaxInt64 {
+		return 0, false
+	}
+	d := time.Duration(days * float64(24*time.Hour))
+	if rest.Len() > 0 {
+		r, err := time.ParseDuration(rest.String())
+		if err != nil || r > math.MaxInt64-d {
+			return 0, false
+		}
+		d += r
+	}
+	if neg {
+		d = -d
 	}
+	return d, true
 }
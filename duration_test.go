@@ -17,6 +17,7 @@
 package cast_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -103,4 +104,63 @@ func TestToDuration(t *testing.T) {
 
 	_, err = cast.ToDurationE("abc")
 	assert.Error(t, err, "time: invalid duration \"abc\"")
+
+	assert.Equal(t, cast.ToDuration("1.5", cast.TimeFormat("s")), 1500*time.Millisecond)
+	assert.Equal(t, cast.ToDuration(cast.StringPtr("3"), cast.TimeFormat("ms")), 3*time.Millisecond)
+	assert.Equal(t, cast.ToDuration("300ms", cast.TimeFormat("s")), 300*time.Millisecond)
+
+	_, err = cast.ToDurationE("abc", cast.TimeFormat("s"))
+	assert.Error(t, err, "time: invalid duration \"abc\"")
+
+	// Only a unit makes a bare number acceptable, not any option.
+	_, err = cast.ToDurationE("15", cast.Strict())
+	assert.Error(t, err, "time: missing unit in duration \"15\"")
+
+	_, err = cast.ToDurationE("15", cast.TimeFormat("2006-01-02"))
+	assert.Error(t, err, "time: missing unit in duration \"15\"")
+
+	assert.Equal(t, cast.ToDuration(15, cast.Strict()), 15*time.Nanosecond)
+	assert.Equal(t, cast.ToDuration("15", cast.TimeFormat("ns")), 15*time.Nanosecond)
+
+	_, err = cast.ToDurationE(10000000, cast.TimeFormat("h"))
+	assert.Error(t, err, "duration 10000000h overflows")
+
+	_, err = cast.ToDurationE(cast.Int64Ptr(-10000000), cast.TimeFormat("h"))
+	assert.Error(t, err, "duration -10000000h overflows")
+
+	_, err = cast.ToDurationE(uint64(math.MaxUint64))
+	assert.Error(t, err, "duration 18446744073709551615ns overflows")
+
+	assert.Equal(t, cast.ToDuration(2562047, cast.TimeFormat("h")), 2562047*time.Hour)
+}
+
+func TestToDurationOr(t *testing.T) {
+	assert.Equal(t, cast.ToDurationOr(nil, time.Second), time.Second)
+	assert.Equal(t, cast.ToDurationOr("abc", time.Second), time.Second)
+	assert.Equal(t, cast.ToDurationOr("3", time.Second, cast.TimeFormat("ms")), 3*time.Millisecond)
+}
+
+func TestToDurationDays(t *testing.T) {
+
+	day := 24 * time.Hour
+	assert.Equal(t, cast.ToDuration("7d"), 7*day)
+	assert.Equal(t, cast.ToDuration("1w"), 7*day)
+	assert.Equal(t, cast.ToDuration("1w2d3h"), 9*day+3*time.Hour)
+	assert.Equal(t, cast.ToDuration("1.5d"), 36*time.Hour)
+	assert.Equal(t, cast.ToDuration("-1d12h30m"), -(36*time.Hour + 30*time.Minute))
+	assert.Equal(t, cast.ToDuration(cast.StringPtr("2d")), 2*day)
+	assert.Equal(t, cast.ToDuration("1d", cast.TimeFormat("s")), day)
+	assert.Equal(t, cast.ToDuration("3h"), 3*time.Hour)
+
+	_, err := cast.ToDurationE("1dd")
+	assert.Error(t, err, "time: unknown unit \"dd\" in duration \"1dd\"")
+
+	_, err = cast.ToDurationE("d")
+	assert.Error(t, err, "time: invalid duration \"d\"")
+
+	_, err = cast.ToDurationE("1d3x")
+	assert.Error(t, err, "time: unknown unit \"d\" in duration \"1d3x\"")
+
+	_, err = cast.ToDurationE("200000w")
+	assert.Error(t, err, "time: unknown unit \"w\" in duration \"200000w\"")
 }
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ToError casts an any to an error. An error is returned as it is, nil
+// and an empty string give nil, and any other value is cast by ToString
+// as the message of a new error, so that an error which became a string
+// in json gets back to an error.
+func ToError(i any) error {
+	switch e := i.(type) {
+	case nil:
+		return nil
+	case error:
+		return e
+	case string:
+		if e == "" {
+			return nil
+		}
+		return errors.New(e)
+	default:
+		if s := ToString(i); s != "" {
+			return errors.New(s)
+		}
+		return nil
+	}
+}
+
+// FromError casts an error to an any that survives json encoding, it's
+// the message of err, or nil when err is nil.
+func FromError(err error) any {
+	if err == nil {
+		return nil
+	}
+	return err.Error()
+}
+
+// ConvertReason tells why a cast failed.
+type ConvertReason int
+
+const (
+	// ReasonUnsupportedType means the type of the value can't be cast to
+	// the target type at all.
+	ReasonUnsupportedType ConvertReason = iota + 1
+	// ReasonParse means a string isn't in a form the target type accepts.
+	ReasonParse
+	// ReasonOverflow means the value is out of the range of the target type.
+	ReasonOverflow
+	// ReasonNegative means a negative value is cast to an unsigned type.
+	ReasonNegative
+	// ReasonNotIntegral means a float with a fractional part is cast to an
+	// integer with the Strict option.
+	ReasonNotIntegral
+	// ReasonNotFinite means a NaN or an infinity is cast with the
+	// RejectNonFinite option, or to a type that can't hold it.
+	ReasonNotFinite
+)
+
+var reasonNames = [...]string{
+	ReasonUnsupportedType: "unsupported type",
+	ReasonParse:           "parse",
+	ReasonOverflow:        "overflow",
+	ReasonNegative:        "negative",
+	ReasonNotIntegral:     "not integral",
+	ReasonNotFinite:       "not finite",
+}
+
+func (r ConvertReason) String() string {
+	if r > 0 && int(r) < len(reasonNames) {
+		return reasonNames[r]
+	}
+	return "ConvertReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// ConvertError is the error returned by the E casts when a value can't be
+// cast, so that callers can tell the failures apart with errors.As. Err is
+// the underlying error, like a *strconv.NumError, when there is one.
+type ConvertError struct {
+	Value      any
+	TargetType string
+	Reason     ConvertReason
+	Err        error
+}
+
+func (e *ConvertError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	switch e.Reason {
+	case ReasonUnsupportedType:
+		return fmt.Sprintf("unable to cast type (%T) to %s", e.Value, e.TargetType)
+	case ReasonOverflow:
+		return fmt.Sprintf("value %v overflows %s", e.Value, e.TargetType)
+	case ReasonNegative:
+		return fmt.Sprintf("unable to cast negative value %v to %s", e.Value, e.TargetType)
+	case ReasonNotIntegral:
+		return fmt.Sprintf("float %v is not integral", e.Value)
+	case ReasonNotFinite:
+		if e.TargetType == "float64" || e.TargetType == "float32" {
+			return fmt.Sprintf("value %v is not finite", e.Value)
+		}
+	}
+	if s, ok := e.Value.(string); ok {
+		return fmt.Sprintf("unable to cast %q to %s", s, e.TargetType)
+	}
+	return fmt.Sprintf("unable to cast %v to %s", e.Value, e.TargetType)
+}
+
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+func unsupportedTypeError(i any, target string) error {
+	return &ConvertError{Value: i, TargetType: target, Reason: ReasonUnsupportedType}
+}
+
+// parseError wraps err of parsing s, an out of range number is reported
+// as an overflow.
+func parseError(s string, target string, err error) error {
+	reason := ReasonParse
+	if errors.Is(err, strconv.ErrRange) {
+		reason = ReasonOverflow
+	}
+	return &ConvertError{Value: s, TargetType: target, Reason: reason, Err: err}
+}
+
+func overflowError(v any, target string) error {
+	return &ConvertError{Value: v, TargetType: target, Reason: ReasonOverflow}
+}
+
+func negativeError(v any, target string) error {
+	return &ConvertError{Value: v, TargetType: target, Reason: ReasonNegative}
+}
+
+// retarget sets the target type of a *ConvertError returned by a wider
+// cast, like ToInt64E for ToInt8E, to the type that was asked for.
+func retarget(err error, target string) error {
+	if e, ok := err.(*ConvertError); ok && e.TargetType != target {
+		c := *e
+		c.TargetType = target
+		return &c
+	}
+	return err
+}
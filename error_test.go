@@ -0,0 +1,115 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToError(t *testing.T) {
+
+	assert.Nil(t, cast.ToError(nil))
+	assert.Nil(t, cast.ToError(""))
+
+	err := errors.New("abc")
+	assert.True(t, cast.ToError(err) == err)
+	assert.Error(t, cast.ToError("abc"), "^abc$")
+	assert.Error(t, cast.ToError([]byte("abc")), "^abc$")
+	assert.Error(t, cast.ToError(3), "^3$")
+}
+
+func TestFromError(t *testing.T) {
+
+	assert.Nil(t, cast.FromError(nil))
+	assert.Equal(t, cast.FromError(errors.New("abc")), "abc")
+
+	var m map[string]any
+	err := cast.JSON.Convert(map[string]any{"err": cast.FromError(errors.New("abc"))}, &m)
+	assert.Nil(t, err)
+	assert.Error(t, cast.ToError(m["err"]), "^abc$")
+}
+
+func TestConvertError(t *testing.T) {
+
+	for _, c := range []struct {
+		f      func() error
+		target string
+		reason cast.ConvertReason
+		msg    string
+	}{
+		{func() error { _, err := cast.ToInt64E([]int{}); return err }, "int64", cast.ReasonUnsupportedType, "unable to cast type \\(\\[\\]int\\) to int64"},
+		{func() error { _, err := cast.ToInt64E("abc"); return err }, "int64", cast.ReasonParse, "strconv.ParseInt: parsing \"abc\": invalid syntax"},
+		{func() error { _, err := cast.ToInt64E("99999999999999999999"); return err }, "int64", cast.ReasonOverflow, "value out of range"},
+		{func() error { _, err := cast.ToInt8E(300); return err }, "int8", cast.ReasonOverflow, "value 300 overflows int8"},
+		{func() error { _, err := cast.ToUintE(-1); return err }, "uint", cast.ReasonNegative, "unable to cast negative value -1 to uint$"},
+		{func() error { _, err := cast.ToUint16E("-1"); return err }, "uint16", cast.ReasonNegative, "unable to cast negative value -1 to uint16"},
+		{func() error { _, err := cast.ToUint8E(-5); return err }, "uint8", cast.ReasonNegative, "unable to cast negative value -5 to uint8"},
+		{func() error { _, err := cast.ToInt8E("abc"); return err }, "int8", cast.ReasonParse, "strconv.ParseInt: parsing \"abc\": invalid syntax"},
+		{func() error { _, err := cast.ToInt32E([]int{}); return err }, "int32", cast.ReasonUnsupportedType, "unable to cast type \\(\\[\\]int\\) to int32"},
+		{func() error { _, err := cast.ToFloat32E("x"); return err }, "float32", cast.ReasonParse, "strconv.ParseFloat"},
+		{func() error { _, err := cast.ToInt64E(1.5, cast.Strict()); return err }, "int64", cast.ReasonNotIntegral, "float 1.5 is not integral"},
+		{func() error { _, err := cast.ToFloat64E(math.NaN(), cast.RejectNonFinite()); return err }, "float64", cast.ReasonNotFinite, "value NaN is not finite"},
+		{func() error { _, err := cast.ToFloat32E(1e300); return err }, "float32", cast.ReasonOverflow, "value 1e\\+300 overflows float32"},
+		{func() error { _, err := cast.ToBoolE("abc"); return err }, "bool", cast.ReasonParse, "unable to cast \"abc\" to bool"},
+		{func() error { _, err := cast.ToDurationE("abc"); return err }, "time.Duration", cast.ReasonParse, "time: invalid duration \"abc\""},
+		{func() error { _, err := cast.ToDurationE(uint64(math.MaxUint64)); return err }, "time.Duration", cast.ReasonOverflow, "duration 18446744073709551615ns overflows"},
+		{func() error { _, err := cast.ToTimeE("abc"); return err }, "Time", cast.ReasonParse, "cannot parse \"abc\""},
+		{func() error { _, err := cast.ToBigIntE(math.Inf(1)); return err }, "*big.Int", cast.ReasonNotFinite, "unable to cast \\+Inf to \\*big.Int"},
+		{func() error { _, err := cast.ToComplex128E("abc"); return err }, "complex128", cast.ReasonParse, "strconv.ParseComplex"},
+		{func() error { _, err := cast.ToComplex128E([]byte("abc")); return err }, "complex128", cast.ReasonParse, "strconv.ParseFloat: parsing \"abc\": invalid syntax"},
+		{func() error { _, err := cast.ToComplex64E([]byte("abc")); return err }, "complex64", cast.ReasonParse, "strconv.ParseFloat: parsing \"abc\": invalid syntax"},
+		{func() error { _, err := cast.ToComplex64E(1e300); return err }, "complex64", cast.ReasonOverflow, "overflows complex64"},
+		{func() error { _, err := cast.ToComplex64E(struct{}{}); return err }, "complex64", cast.ReasonUnsupportedType, "unable to cast type \\(struct {}\\) to complex64"},
+		{func() error { _, err := cast.ToStringE(make(chan int)); return err }, "string", cast.ReasonUnsupportedType, "unable to cast type \\(chan int\\) to string"},
+	} {
+		err := c.f()
+		var e *cast.ConvertError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, e.TargetType, c.target)
+		assert.Equal(t, e.Reason, c.reason)
+		assert.Error(t, err, c.msg)
+	}
+
+	_, err := cast.ToInt64E("abc")
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+
+	_, err = cast.ToSlice[int]([]any{1, "a"})
+	var e *cast.ConvertError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.Value, "a")
+	assert.Equal(t, e.Reason, cast.ReasonParse)
+
+	type Level int8
+	_, err = cast.To[Level]("abc", cast.Weak())
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.TargetType, "int8")
+
+	_, err = cast.ToTimeE(true)
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.Value, true)
+
+	assert.Equal(t, cast.ReasonOverflow.String(), "overflow")
+	assert.Equal(t, cast.ConvertReason(100).String(), "ConvertReason(100)")
+	assert.Equal(t, cast.ToDuration("abc"), time.Duration(0))
+}
@@ -17,27 +17,74 @@
 package cast
 
 import (
-	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ToFloat32 casts an any to a float32.
 // When type is clear, it is recommended to use standard library functions.
-func ToFloat32(i any) float32 {
-	v, _ := ToFloat64E(i)
-	return float32(v)
+func ToFloat32(i any, opts ...Option) float32 {
+	v, _ := ToFloat32E(i, opts...)
+	return v
+}
+
+// ToFloat32E casts an any to a float32, it returns an error when a finite
+// value is out of the range of float32.
+// When type is clear, it is recommended to use standard library functions.
+func ToFloat32E(i any, opts ...Option) (float32, error) {
+	v, err := ToFloat64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "float32")
+	}
+	if !math.IsInf(v, 0) && math.Abs(v) > math.MaxFloat32 {
+		return 0, overflowError(v, "float32")
+	}
+	return float32(v), nil
 }
 
 // ToFloat64 casts an any to a float64.
 // When type is clear, it is recommended to use standard library functions.
-func ToFloat64(i any) float64 {
-	v, _ := ToFloat64E(i)
+func ToFloat64(i any, opts ...Option) float64 {
+	v, _ := ToFloat64E(i, opts...)
 	return v
 }
 
-// ToFloat64E casts an any to a float64.
+// ToFloat64Or casts an any to a float64, it returns def when i is nil or
+// the cast fails.
+func ToFloat64Or(i any, def float64, opts ...Option) float64 {
+	if i == nil {
+		return def
+	}
+	v, err := ToFloat64E(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ToFloat64E casts an any to a float64. A time.Duration is its count of
+// nanoseconds, and a time.Time is its Unix time in nanoseconds. With the
+// RejectNonFinite option a NaN or an infinity, either given or parsed from
+// a string, is an error.
 // When type is clear, it is recommended to use standard library functions.
-func ToFloat64E(i any) (float64, error) {
+func ToFloat64E(i any, opts ...Option) (float64, error) {
+	v, err := toFloat64(i, opts...)
+	if err != nil || len(opts) == 0 {
+		return v, err
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	if arg.RejectNonFinite && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return 0, &ConvertError{Value: v, TargetType: "float64", Reason: ReasonNotFinite}
+	}
+	return v, nil
+}
+
+func toFloat64(i any, opts ...Option) (float64, error) {
 	switch s := i.(type) {
 	case nil:
 		return 0, nil
@@ -90,9 +137,9 @@ func ToFloat64E(i any) (float64, error) {
 	case *float64:
 		return *s, nil
 	case string:
-		return strconv.ParseFloat(s, 64)
+		return parseFloat64(s, opts...)
 	case *string:
-		return strconv.ParseFloat(*s, 64)
+		return parseFloat64(*s, opts...)
 	case bool:
 		if s {
 			return 1, nil
@@ -103,7 +150,49 @@ func ToFloat64E(i any) (float64, error) {
 			return 1, nil
 		}
 		return 0, nil
+	case []byte:
+		return parseFloat64(string(s), opts...)
+	case time.Duration:
+		return float64(s), nil
+	case *time.Duration:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "float64")
+		}
+		return float64(*s), nil
+	case time.Time:
+		return float64(s.UnixNano()), nil
+	case *time.Time:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "float64")
+		}
+		return float64(s.UnixNano()), nil
 	default:
-		return 0, fmt.Errorf("unable to cast type (%T) to float64", i)
+		if v, ok := basicOf(i); ok {
+			return toFloat64(v, opts...)
+		}
+		return 0, unsupportedTypeError(i, "float64")
+	}
+}
+
+func parseFloat64(s string, opts ...Option) (float64, error) {
+	s = strings.TrimSpace(s)
+	if len(opts) == 0 {
+		return parseFloat(s)
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	if s == "" && arg.EmptyAsZero {
+		return 0, nil
+	}
+	return parseFloat(delocalize(s, &arg))
+}
+
+func parseFloat(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return v, parseError(s, "float64", err)
 	}
+	return v, nil
 }
@@ -17,9 +17,12 @@
 package cast_test
 
 import (
+	"bytes"
 	"errors"
+	"math"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/lvan100/cast"
 	"github.com/lvan100/cast/internal/assert"
@@ -88,9 +91,111 @@ func TestToFloat(t *testing.T) {
 	assert.Equal(t, cast.ToFloat64(cast.BoolPtr(true)), float64(1))
 	assert.Equal(t, cast.ToFloat64(cast.BoolPtr(false)), float64(0))
 
-	_, err := cast.ToFloat64E("abc")
+	assert.Equal(t, cast.ToFloat64(" 3.14\n"), 3.14)
+	assert.Equal(t, cast.ToFloat64(cast.StringPtr("\t3.14 ")), 3.14)
+
+	type Rate float64
+	assert.Equal(t, cast.ToFloat64([]byte("3.14")), 3.14)
+	assert.Equal(t, cast.ToFloat64(Rate(3.14)), 3.14)
+	assert.Equal(t, cast.ToFloat64(bytes.NewBufferString("3.14")), 3.14)
+
+	_, err := cast.ToFloat64E(" abc ")
+	assert.Error(t, err, "strconv.ParseFloat: parsing \"abc\": invalid syntax")
+
+	_, err = cast.ToFloat64E("abc")
 	assert.Error(t, err, "strconv.ParseFloat: parsing \"abc\": invalid syntax")
 
 	_, err = cast.ToFloat64E(errors.New("abc"))
 	assert.Error(t, err, "unable to cast type \\(\\*errors\\.errorString\\) to float64")
 }
+
+func TestToFloat64Or(t *testing.T) {
+	assert.Equal(t, cast.ToFloat64Or(nil, 1.5), 1.5)
+	assert.Equal(t, cast.ToFloat64Or("abc", 1.5), 1.5)
+	assert.Equal(t, cast.ToFloat64Or("0", 1.5), float64(0))
+}
+
+func TestToFloat64RejectNonFinite(t *testing.T) {
+
+	assert.True(t, math.IsNaN(cast.ToFloat64("NaN")))
+	assert.True(t, math.IsInf(cast.ToFloat64("+Inf"), 1))
+
+	for _, i := range []any{"NaN", "Inf", "+Inf", "-Inf", math.NaN(), math.Inf(1), float32(math.Inf(-1))} {
+		_, err := cast.ToFloat64E(i, cast.RejectNonFinite())
+		assert.Error(t, err, "value (NaN|\\+Inf|-Inf) is not finite")
+	}
+
+	v, err := cast.ToFloat64E("1.5", cast.RejectNonFinite())
+	assert.Nil(t, err)
+	assert.Equal(t, v, 1.5)
+
+	assert.Equal(t, cast.ToFloat64Or("NaN", 1.5, cast.RejectNonFinite()), 1.5)
+
+	_, err = cast.To[float64]("Inf", cast.RejectNonFinite())
+	assert.Error(t, err, "value \\+Inf is not finite")
+}
+
+func TestToFloat32(t *testing.T) {
+
+	assert.Equal(t, cast.ToFloat32(1.5), float32(1.5))
+	assert.Equal(t, cast.ToFloat32("3.25"), float32(3.25))
+	assert.Equal(t, cast.ToFloat32(math.MaxFloat32), float32(math.MaxFloat32))
+	assert.Equal(t, cast.ToFloat32(1e-40), float32(1e-40))
+	assert.True(t, math.IsInf(float64(cast.ToFloat32(math.Inf(1))), 1))
+
+	_, err := cast.ToFloat32E(1e40)
+	assert.Error(t, err, "value 1e\\+40 overflows float32")
+	_, err = cast.ToFloat32E("-1e40")
+	assert.Error(t, err, "value -1e\\+40 overflows float32")
+
+	_, err = cast.To[float32](1e40)
+	assert.Error(t, err, "value 1e\\+40 overflows float32")
+}
+
+func TestToFloat64DecimalSeparators(t *testing.T) {
+
+	assert.Equal(t, cast.ToFloat64("1,234.56", cast.DecimalSeparators(',', '.')), 1234.56)
+	assert.Equal(t, cast.ToFloat64("1.234,56", cast.DecimalSeparators('.', ',')), 1234.56)
+	assert.Equal(t, cast.ToFloat64("1 234,5", cast.DecimalSeparators(' ', ',')), 1234.5)
+	assert.Equal(t, cast.ToFloat64("-1,000,000", cast.DecimalSeparators(',', '.')), -1e6)
+
+	_, err := cast.ToFloat64E("1,234.56")
+	assert.Error(t, err, "strconv.ParseFloat: parsing \"1,234.56\": invalid syntax")
+}
+
+func TestToFloat64Time(t *testing.T) {
+
+	d := 1500 * time.Millisecond
+	assert.Equal(t, cast.ToFloat64(time.Second), 1e9)
+	assert.Equal(t, cast.ToFloat64(&d), 1.5e9)
+	assert.Equal(t, cast.ToInt64(time.Second), int64(1e9))
+	assert.Equal(t, cast.ToInt64(&d), int64(1.5e9))
+	assert.Equal(t, cast.ToUint64(time.Second), uint64(1e9))
+
+	tm := time.Unix(2, 5)
+	assert.Equal(t, cast.ToInt64(tm), int64(2e9+5))
+	assert.Equal(t, cast.ToInt64(&tm), int64(2e9+5))
+	assert.Equal(t, cast.ToUint64(tm), uint64(2e9+5))
+	assert.Equal(t, cast.ToFloat64(tm), float64(2e9+5))
+
+	_, err := cast.ToUint64E(-time.Second)
+	assert.Error(t, err, "unable to cast negative value -1000000000 to uint64")
+
+	_, err = cast.ToInt32E(time.Hour)
+	assert.Error(t, err, "value 3600000000000 overflows int32")
+
+	for _, c := range []struct {
+		f   func(any) error
+		i   any
+		msg string
+	}{
+		{func(i any) error { _, err := cast.ToInt64E(i); return err }, (*time.Time)(nil), "unable to cast type \\(\\*time.Time\\) to int64"},
+		{func(i any) error { _, err := cast.ToInt64E(i); return err }, (*time.Duration)(nil), "unable to cast type \\(\\*time.Duration\\) to int64"},
+		{func(i any) error { _, err := cast.ToUint64E(i); return err }, (*time.Time)(nil), "unable to cast type \\(\\*time.Time\\) to uint64"},
+		{func(i any) error { _, err := cast.ToUint64E(i); return err }, (*time.Duration)(nil), "unable to cast type \\(\\*time.Duration\\) to uint64"},
+		{func(i any) error { _, err := cast.ToFloat64E(i); return err }, (*time.Time)(nil), "unable to cast type \\(\\*time.Time\\) to float64"},
+		{func(i any) error { _, err := cast.ToFloat64E(i); return err }, (*time.Duration)(nil), "unable to cast type \\(\\*time.Duration\\) to float64"},
+	} {
+		assert.Error(t, c.f(c.i), c.msg)
+	}
+}
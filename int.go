@@ -17,48 +17,122 @@
 package cast
 
 import (
-	"fmt"
+	"errors"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ToInt casts an any to an int.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt(i any) int {
-	v, _ := ToInt64E(i)
-	return int(v)
+func ToInt(i any, opts ...Option) int {
+	v, _ := ToIntE(i, opts...)
+	return v
+}
+
+// ToIntOr casts an any to an int, it returns def when i is nil or the
+// cast fails.
+func ToIntOr(i any, def int, opts ...Option) int {
+	if i == nil {
+		return def
+	}
+	v, err := ToIntE(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ToIntE casts an any to an int.
+// When type is clear, it is recommended to use standard library functions.
+func ToIntE(i any, opts ...Option) (int, error) {
+	v, err := ToInt64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "int")
+	}
+	v, err = narrowInt(v, 0, opts)
+	return int(v), err
 }
 
 // ToInt8 casts an any to an int8.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt8(i any) int8 {
-	v, _ := ToInt64E(i)
-	return int8(v)
+func ToInt8(i any, opts ...Option) int8 {
+	v, _ := ToInt8E(i, opts...)
+	return v
+}
+
+// ToInt8E casts an any to an int8.
+// When type is clear, it is recommended to use standard library functions.
+func ToInt8E(i any, opts ...Option) (int8, error) {
+	v, err := ToInt64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "int8")
+	}
+	v, err = narrowInt(v, 8, opts)
+	return int8(v), err
 }
 
 // ToInt16 casts an any to an int16.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt16(i any) int16 {
-	v, _ := ToInt64E(i)
-	return int16(v)
+func ToInt16(i any, opts ...Option) int16 {
+	v, _ := ToInt16E(i, opts...)
+	return v
+}
+
+// ToInt16E casts an any to an int16.
+// When type is clear, it is recommended to use standard library functions.
+func ToInt16E(i any, opts ...Option) (int16, error) {
+	v, err := ToInt64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "int16")
+	}
+	v, err = narrowInt(v, 16, opts)
+	return int16(v), err
 }
 
 // ToInt32 casts an any to an int32.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt32(i any) int32 {
-	v, _ := ToInt64E(i)
-	return int32(v)
+func ToInt32(i any, opts ...Option) int32 {
+	v, _ := ToInt32E(i, opts...)
+	return v
+}
+
+// ToInt32E casts an any to an int32.
+// When type is clear, it is recommended to use standard library functions.
+func ToInt32E(i any, opts ...Option) (int32, error) {
+	v, err := ToInt64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "int32")
+	}
+	v, err = narrowInt(v, 32, opts)
+	return int32(v), err
 }
 
 // ToInt64 casts an any to an int64.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt64(i any) int64 {
-	v, _ := ToInt64E(i)
+func ToInt64(i any, opts ...Option) int64 {
+	v, _ := ToInt64E(i, opts...)
+	return v
+}
+
+// ToInt64Or casts an any to an int64, it returns def when i is nil or
+// the cast fails.
+func ToInt64Or(i any, def int64, opts ...Option) int64 {
+	if i == nil {
+		return def
+	}
+	v, err := ToInt64E(i, opts...)
+	if err != nil {
+		return def
+	}
 	return v
 }
 
-// ToInt64E casts an any to an int64.
+// ToInt64E casts an any to an int64. A time.Duration is its count of
+// nanoseconds, and a time.Time is its Unix time in nanoseconds.
 // When type is clear, it is recommended to use standard library functions.
-func ToInt64E(i any) (int64, error) {
+func ToInt64E(i any, opts ...Option) (int64, error) {
 	switch s := i.(type) {
 	case nil:
 		return 0, nil
@@ -91,7 +165,7 @@ func ToInt64E(i any) (int64, error) {
 	case uint32:
 		return int64(s), nil
 	case uint64:
-		return int64(s), nil
+		return uint64ToInt64(s, opts...)
 	case *uint:
 		return int64(*s), nil
 	case *uint8:
@@ -101,19 +175,23 @@ func ToInt64E(i any) (int64, error) {
 	case *uint32:
 		return int64(*s), nil
 	case *uint64:
-		return int64(*s), nil
+		return uint64ToInt64(*s, opts...)
+	case uintptr:
+		return uint64ToInt64(uint64(s), opts...)
+	case *uintptr:
+		return uint64ToInt64(uint64(*s), opts...)
 	case float32:
-		return int64(s), nil
+		return floatToInt64(float64(s), opts...)
 	case float64:
-		return int64(s), nil
+		return floatToInt64(s, opts...)
 	case *float32:
-		return int64(*s), nil
+		return floatToInt64(float64(*s), opts...)
 	case *float64:
-		return int64(*s), nil
+		return floatToInt64(*s, opts...)
 	case string:
-		return strconv.ParseInt(s, 0, 0)
+		return parseInt64(s, opts...)
 	case *string:
-		return strconv.ParseInt(*s, 0, 0)
+		return parseInt64(*s, opts...)
 	case bool:
 		if s {
 			return 1, nil
@@ -124,6 +202,146 @@ func ToInt64E(i any) (int64, error) {
 			return 1, nil
 		}
 		return 0, nil
+	case []byte:
+		return parseInt64(string(s), opts...)
+	case time.Duration:
+		return int64(s), nil
+	case *time.Duration:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "int64")
+		}
+		return int64(*s), nil
+	case time.Time:
+		return s.UnixNano(), nil
+	case *time.Time:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "int64")
+		}
+		return s.UnixNano(), nil
+	}
+	if v, ok := basicOf(i); ok {
+		return ToInt64E(v, opts...)
+	}
+	return 0, unsupportedTypeError(i, "int64")
+}
+
+// narrowInt checks that v fits in a signed integer of the bit size, 0
+// means int. With the Saturate option v is clamped to the range instead.
+func narrowInt(v int64, bitSize int, opts []Option) (int64, error) {
+	name := "int"
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	} else {
+		name += strconv.Itoa(bitSize)
+	}
+	maxV := int64(1)<<(bitSize-1) - 1
+	minV := -maxV - 1
+	if v >= minV && v <= maxV {
+		return v, nil
+	}
+	if !saturate(opts) {
+		return 0, overflowError(v, name)
+	}
+	if v < minV {
+		return minV, nil
+	}
+	return maxV, nil
+}
+
+func uint64ToInt64(v uint64, opts ...Option) (int64, error) {
+	if v > math.MaxInt64 {
+		if saturate(opts) {
+			return math.MaxInt64, nil
+		}
+		return 0, overflowError(v, "int64")
+	}
+	return int64(v), nil
+}
+
+func floatToInt64(v float64, opts ...Option) (int64, error) {
+	v, err := integral(v, "int64", opts...)
+	if err != nil {
+		return 0, err
+	}
+	if !math.IsNaN(v) && (v < math.MinInt64 || v >= math.MaxInt64) && saturate(opts) {
+		if v < 0 {
+			return math.MinInt64, nil
+		}
+		return math.MaxInt64, nil
+	}
+	if math.IsNaN(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, overflowError(v, "int64")
+	}
+	return int64(v), nil
+}
+
+// integral rounds v by the Rounding option, or returns an error for a
+// float with a fractional part when the Strict option is given. Without
+// them v is left as is and truncated by the conversion.
+func integral(v float64, target string, opts ...Option) (float64, error) {
+	if len(opts) == 0 || v == math.Trunc(v) {
+		return v, nil
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	switch arg.Rounding {
+	case Round:
+		return math.Round(v), nil
+	case Floor:
+		return math.Floor(v), nil
+	case Ceil:
+		return math.Ceil(v), nil
+	}
+	if arg.Strict {
+		return 0, &ConvertError{Value: v, TargetType: target, Reason: ReasonNotIntegral}
+	}
+	return v, nil
+}
+
+func parseInt64(s string, opts ...Option) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" && emptyAsZero(opts) {
+		return 0, nil
+	}
+	if len(opts) == 0 {
+		v, err := strconv.ParseInt(s, 0, 0)
+		if err != nil {
+			return v, parseError(s, "int64", err)
+		}
+		return v, nil
+	}
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	if arg.StripUnderscores {
+		s = strings.ReplaceAll(s, "_", "")
+	}
+	s = delocalize(s, &arg)
+	v, err := strconv.ParseInt(s, arg.Base, 0)
+	if err != nil && arg.AllowFloatString {
+		if f, e := strconv.ParseFloat(s, 64); e == nil {
+			return floatToInt64(f, opts...)
+		}
+	}
+	// Bool tokens come last, so a string that is a number is never read
+	// as a bool, and "" isn't one here.
+	if err != nil && arg.Weak && s != "" {
+		if b, e := parseBool(s); e == nil {
+			if b {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if err != nil {
+		// ParseInt gives the nearest bound for an out of range number.
+		if arg.Saturate && errors.Is(err, strconv.ErrRange) {
+			return v, nil
+		}
+		return v, parseError(s, "int64", err)
 	}
-	return 0, fmt.Errorf("unable to cast type (%T) to int64", i)
+	return v, nil
 }
@@ -17,7 +17,9 @@
 package cast_test
 
 import (
+	"bytes"
 	"errors"
+	"math"
 	"strconv"
 	"testing"
 
@@ -88,9 +90,300 @@ func TestToInt(t *testing.T) {
 	assert.Equal(t, cast.ToInt64(cast.BoolPtr(true)), int64(1))
 	assert.Equal(t, cast.ToInt64(cast.BoolPtr(false)), int64(0))
 
+	assert.Equal(t, cast.ToInt64(" 42 "), int64(42))
+	assert.Equal(t, cast.ToInt64(cast.StringPtr("42\n")), int64(42))
+
+	type ID int64
+	type Name string
+	assert.Equal(t, cast.ToInt64([]byte("42")), int64(42))
+	assert.Equal(t, cast.ToInt64(ID(42)), int64(42))
+	assert.Equal(t, cast.ToInt64(Name("42")), int64(42))
+	assert.Equal(t, cast.ToInt64(bytes.NewBufferString("42")), int64(42))
+	assert.Equal(t, cast.ToInt(ID(42)), 42)
+
 	_, err := cast.ToInt64E("abc")
 	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
 
 	_, err = cast.ToInt64E(errors.New("abc"))
 	assert.Error(t, err, "unable to cast type \\(\\*errors\\.errorString\\) to int64")
+
+	_, err = cast.ToInt64E(float64(1e20))
+	assert.Error(t, err, "value 1e\\+20 overflows int64")
+
+	_, err = cast.ToInt64E(uint64(math.MaxUint64))
+	assert.Error(t, err, "value 18446744073709551615 overflows int64")
+
+	assert.Equal(t, cast.ToInt8(300), int8(0))
+
+	_, err = cast.ToInt8E(300)
+	assert.Error(t, err, "value 300 overflows int8")
+
+	_, err = cast.ToInt16E("-40000")
+	assert.Error(t, err, "value -40000 overflows int16")
+
+	_, err = cast.ToInt32E(float64(math.MaxInt32 + 1))
+	assert.Error(t, err, "value 2147483648 overflows int32")
+
+	v8, err := cast.ToInt8E(-128)
+	assert.Nil(t, err)
+	assert.Equal(t, v8, int8(-128))
+
+	_, err = cast.To[int8](300)
+	assert.Error(t, err, "value 300 overflows int8")
+}
+
+func TestToIntAllowFloatString(t *testing.T) {
+
+	_, err := cast.ToInt64E("3.0")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"3.0\": invalid syntax")
+
+	assert.Equal(t, cast.ToInt64("3.0", cast.AllowFloatString()), int64(3))
+	assert.Equal(t, cast.ToInt64("3.9", cast.AllowFloatString()), int64(3))
+	assert.Equal(t, cast.ToInt64("-3.9", cast.AllowFloatString()), int64(-3))
+	assert.Equal(t, cast.ToInt64("3e2", cast.AllowFloatString()), int64(300))
+	assert.Equal(t, cast.ToInt(cast.StringPtr(" 3.0 "), cast.AllowFloatString()), 3)
+
+	v, err := cast.To[int8]("3.5", cast.AllowFloatString())
+	assert.Nil(t, err)
+	assert.Equal(t, v, int8(3))
+
+	_, err = cast.ToInt8E("3e2", cast.AllowFloatString())
+	assert.Error(t, err, "value 300 overflows int8")
+
+	_, err = cast.ToInt64E("abc", cast.AllowFloatString())
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+}
+
+func TestToIntBase(t *testing.T) {
+
+	assert.Equal(t, cast.ToInt64("0x1F"), int64(31))
+	assert.Equal(t, cast.ToInt64("010"), int64(8))
+	assert.Equal(t, cast.ToInt64("1_000_000"), int64(1000000))
+
+	assert.Equal(t, cast.ToInt64("010", cast.Base(10)), int64(10))
+	assert.Equal(t, cast.ToInt64("1F", cast.Base(16)), int64(31))
+
+	_, err := cast.ToInt64E("0x1F", cast.Base(10))
+	assert.Error(t, err, "strconv.ParseInt: parsing \"0x1F\": invalid syntax")
+
+	_, err = cast.ToInt64E("1_000_000", cast.Base(10))
+	assert.Error(t, err, "strconv.ParseInt: parsing \"1_000_000\": invalid syntax")
+
+	assert.Equal(t, cast.ToInt64("1_000_000", cast.Base(10), cast.StripUnderscores()), int64(1000000))
+	assert.Equal(t, cast.ToInt64("1_000.5", cast.StripUnderscores(), cast.AllowFloatString()), int64(1000))
+}
+
+func TestToIntOr(t *testing.T) {
+	assert.Equal(t, cast.ToIntOr(nil, 7), 7)
+	assert.Equal(t, cast.ToIntOr("abc", 7), 7)
+	assert.Equal(t, cast.ToIntOr("0", 7), 0)
+	assert.Equal(t, cast.ToIntOr("3.5", 7, cast.AllowFloatString()), 3)
+	assert.Equal(t, cast.ToInt64Or(nil, 7), int64(7))
+	assert.Equal(t, cast.ToInt64Or(1e20, 7), int64(7))
+	assert.Equal(t, cast.ToInt64Or("3", 7), int64(3))
+}
+
+func TestToIntStrict(t *testing.T) {
+
+	assert.Equal(t, cast.ToInt(3.9), 3)
+	assert.Equal(t, cast.ToInt(3.0, cast.Strict()), 3)
+	assert.Equal(t, cast.ToInt64(-2.0, cast.Strict()), int64(-2))
+
+	_, err := cast.ToIntE(3.9, cast.Strict())
+	assert.Error(t, err, "float 3.9 is not integral")
+
+	_, err = cast.ToInt64E(float32(1.5), cast.Strict())
+	assert.Error(t, err, "float 1.5 is not integral")
+
+	_, err = cast.ToInt8E(300, cast.Strict())
+	assert.Error(t, err, "value 300 overflows int8")
+
+	_, err = cast.ToInt64E("3.5", cast.AllowFloatString(), cast.Strict())
+	assert.Error(t, err, "float 3.5 is not integral")
+
+	_, err = cast.ToUint64E(3.5, cast.Strict())
+	assert.Error(t, err, "float 3.5 is not integral")
+
+	_, err = cast.To[int](2.5, cast.Strict())
+	assert.Error(t, err, "float 2.5 is not integral")
+
+	_, err = cast.ToInt64E(cast.Float64Ptr(3.5), cast.Strict())
+	assert.Error(t, err, "float 3.5 is not integral")
+
+	_, err = cast.ToInt64E(cast.Float32Ptr(-0.25), cast.Strict())
+	assert.Error(t, err, "float -0.25 is not integral")
+
+	assert.Equal(t, cast.ToInt64(cast.Float64Ptr(4), cast.Strict()), int64(4))
+	assert.Equal(t, cast.ToInt64(cast.Float32Ptr(4), cast.Strict()), int64(4))
+
+	_, err = cast.ToInt64E(math.NaN(), cast.Strict())
+	assert.Error(t, err, "float NaN is not integral")
+
+	_, err = cast.ToInt64E(math.Inf(1), cast.Strict())
+	assert.Error(t, err, "value \\+Inf overflows int64")
+}
+
+func TestToIntDecimalSeparators(t *testing.T) {
+
+	assert.Equal(t, cast.ToInt64("1,234", cast.DecimalSeparators(',', '.')), int64(1234))
+	assert.Equal(t, cast.ToInt64("-1.234.567", cast.DecimalSeparators('.', ',')), int64(-1234567))
+	assert.Equal(t, cast.ToUint64("1.234", cast.DecimalSeparators('.', ',')), uint64(1234))
+	assert.Equal(t, cast.ToInt64("1.234,9", cast.DecimalSeparators('.', ','), cast.AllowFloatString()), int64(1234))
+
+	_, err := cast.ToInt64E("1.234,9", cast.DecimalSeparators('.', ','))
+	assert.Error(t, err, "strconv.ParseInt: parsing \"1234.9\": invalid syntax")
+}
+
+func TestToIntRounding(t *testing.T) {
+
+	assert.Equal(t, cast.ToInt(3.9), 3)
+	assert.Equal(t, cast.ToInt(3.9, cast.Rounding(cast.Truncate)), 3)
+	assert.Equal(t, cast.ToInt(3.9, cast.Rounding(cast.Round)), 4)
+	assert.Equal(t, cast.ToInt(-2.5, cast.Rounding(cast.Round)), -3)
+	assert.Equal(t, cast.ToInt(3.9, cast.Rounding(cast.Floor)), 3)
+	assert.Equal(t, cast.ToInt(-3.1, cast.Rounding(cast.Floor)), -4)
+	assert.Equal(t, cast.ToInt64(cast.Float32Ptr(3.1), cast.Rounding(cast.Ceil)), int64(4))
+	assert.Equal(t, cast.ToInt64("3.5", cast.AllowFloatString(), cast.Rounding(cast.Round)), int64(4))
+	assert.Equal(t, cast.ToInt(3.5, cast.Rounding(cast.Round), cast.Strict()), 4)
+
+	assert.Equal(t, cast.ToUint(2.5, cast.Rounding(cast.Round)), uint(3))
+	assert.Equal(t, cast.ToUint(-0.4, cast.Rounding(cast.Round)), uint(0))
+
+	v, err := cast.To[int8](126.5, cast.Rounding(cast.Ceil))
+	assert.Nil(t, err)
+	assert.Equal(t, v, int8(127))
+
+	_, err = cast.ToInt8E(127.5, cast.Rounding(cast.Round))
+	assert.Error(t, err, "value 128 overflows int8")
+
+	_, err = cast.ToUintE(-0.6, cast.Rounding(cast.Round))
+	assert.Error(t, err, "unable to cast negative value -1 to uint$")
+}
+
+func TestToIntUintptr(t *testing.T) {
+
+	p := uintptr(42)
+	assert.Equal(t, cast.ToInt64(p), int64(42))
+	assert.Equal(t, cast.ToInt64(&p), int64(42))
+	assert.Equal(t, cast.ToUint64(p), uint64(42))
+	assert.Equal(t, cast.ToUint64(&p), uint64(42))
+	assert.Equal(t, cast.ToString(p), "42")
+	assert.Equal(t, cast.ToString(&p), "42")
+
+	_, err := cast.ToInt8E(uintptr(300))
+	assert.Error(t, err, "value 300 overflows int8")
+
+	// rune and byte are aliases of int32 and uint8.
+	assert.Equal(t, cast.ToInt64('a'), int64(97))
+	assert.Equal(t, cast.ToInt32(rune(-1)), int32(-1))
+	assert.Equal(t, cast.ToInt64(byte(255)), int64(255))
+	assert.Equal(t, cast.ToUint8(byte(7)), uint8(7))
+	assert.Equal(t, cast.ToString('a'), "97")
+	assert.Equal(t, cast.ToString(byte('a')), "97")
+
+	v, err := cast.To[rune]("97")
+	assert.Nil(t, err)
+	assert.Equal(t, v, 'a')
+
+	b, err := cast.To[byte](97)
+	assert.Nil(t, err)
+	assert.Equal(t, b, byte('a'))
+
+	_, err = cast.To[byte](256)
+	assert.Error(t, err, "value 256 overflows uint8")
+}
+
+func TestToIntWeakBool(t *testing.T) {
+
+	_, err := cast.ToInt64E("true")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"true\": invalid syntax")
+
+	assert.Equal(t, cast.ToInt64("true", cast.Weak()), int64(1))
+	assert.Equal(t, cast.ToInt64("false", cast.Weak()), int64(0))
+	assert.Equal(t, cast.ToInt64(" TRUE ", cast.Weak()), int64(1))
+	assert.Equal(t, cast.ToInt([]byte("false"), cast.Weak()), 0)
+	assert.Equal(t, cast.ToInt8("true", cast.Weak()), int8(1))
+	assert.Equal(t, cast.ToInt64("7", cast.Weak()), int64(7))
+	assert.Equal(t, cast.ToInt64("2.5", cast.Weak(), cast.AllowFloatString()), int64(2))
+
+	v, err := cast.To[int]("true", cast.Weak())
+	assert.Nil(t, err)
+	assert.Equal(t, v, 1)
+
+	_, err = cast.ToInt64E("", cast.Weak())
+	assert.Error(t, err, "strconv.ParseInt: parsing \"\": invalid syntax")
+
+	_, err = cast.ToInt64E("abc", cast.Weak())
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+}
+
+func TestToIntSaturate(t *testing.T) {
+
+	assert.Equal(t, cast.ToInt8(300), int8(0))
+	assert.Equal(t, cast.ToInt8(300, cast.Saturate()), int8(math.MaxInt8))
+	assert.Equal(t, cast.ToInt8(-300, cast.Saturate()), int8(math.MinInt8))
+	assert.Equal(t, cast.ToInt8(100, cast.Saturate()), int8(100))
+	assert.Equal(t, cast.ToInt16("70000", cast.Saturate()), int16(math.MaxInt16))
+	assert.Equal(t, cast.ToInt32(float64(-1e10), cast.Saturate()), int32(math.MinInt32))
+	assert.Equal(t, cast.ToInt(int64(math.MaxInt64), cast.Saturate()), int(math.MaxInt64))
+
+	assert.Equal(t, cast.ToUint8(300, cast.Saturate()), uint8(math.MaxUint8))
+	assert.Equal(t, cast.ToUint16(uint64(math.MaxUint64), cast.Saturate()), uint16(math.MaxUint16))
+	assert.Equal(t, cast.ToUint32("5000000000", cast.Saturate()), uint32(math.MaxUint32))
+	assert.Equal(t, cast.ToUint(uint64(math.MaxUint64), cast.Saturate()), uint(math.MaxUint64))
+
+	assert.Equal(t, cast.ToUint8(-5, cast.Saturate()), uint8(0))
+	assert.Equal(t, cast.ToUint("-5", cast.Saturate()), uint(0))
+	assert.Equal(t, cast.ToUint64(-1.5, cast.Saturate()), uint64(0))
+	assert.Equal(t, cast.ToUint32(cast.IntPtr(-1), cast.Saturate()), uint32(0))
+
+	u, err := cast.ToUint8E(-5, cast.Saturate())
+	assert.Nil(t, err)
+	assert.Equal(t, u, uint8(0))
+
+	_, err = cast.ToUint8E(-5)
+	assert.Error(t, err, "unable to cast negative value -5 to uint8")
+
+	_, err = cast.ToUint8E("abc", cast.Saturate())
+	assert.Error(t, err, "strconv.ParseUint: parsing \"abc\": invalid syntax")
+
+	v, err := cast.To[int8](1000, cast.Saturate())
+	assert.Nil(t, err)
+	assert.Equal(t, v, int8(math.MaxInt8))
+
+	// Sources beyond the range of int64 and uint64 clamp too.
+	assert.Equal(t, cast.ToInt8(1e30, cast.Saturate()), int8(math.MaxInt8))
+	assert.Equal(t, cast.ToInt8(-1e30, cast.Saturate()), int8(math.MinInt8))
+	assert.Equal(t, cast.ToInt64(1e30, cast.Saturate()), int64(math.MaxInt64))
+	assert.Equal(t, cast.ToInt8(uint64(math.MaxUint64), cast.Saturate()), int8(math.MaxInt8))
+	assert.Equal(t, cast.ToInt64(uint64(math.MaxUint64), cast.Saturate()), int64(math.MaxInt64))
+	assert.Equal(t, cast.ToInt8("99999999999999999999", cast.Saturate()), int8(math.MaxInt8))
+	assert.Equal(t, cast.ToInt8("-99999999999999999999", cast.Saturate()), int8(math.MinInt8))
+	assert.Equal(t, cast.ToUint8(1e30, cast.Saturate()), uint8(math.MaxUint8))
+	assert.Equal(t, cast.ToUint64(1e30, cast.Saturate()), uint64(math.MaxUint64))
+	assert.Equal(t, cast.ToUint8("99999999999999999999", cast.Saturate()), uint8(math.MaxUint8))
+	assert.Equal(t, cast.ToUint8("-99999999999999999999", cast.Saturate()), uint8(0))
+
+	_, err = cast.ToInt8E(1e30)
+	assert.Error(t, err, "value 1e\\+30 overflows int8")
+
+	_, err = cast.ToInt8E("99999999999999999999")
+	assert.Error(t, err, "value out of range")
+
+	_, err = cast.ToUint8E(1e30)
+	assert.Error(t, err, "value 1e\\+30 overflows uint8")
+
+	_, err = cast.ToInt8E(math.NaN(), cast.Saturate())
+	assert.Error(t, err, "value NaN overflows int8")
+
+	_, err = cast.ToUint8E("-99999999999999999999")
+	assert.Error(t, err, "unable to cast negative value -99999999999999999999 to uint8")
+
+	type Level int8
+	l, err := cast.To[Level]("-1000", cast.Weak(), cast.Saturate())
+	assert.Nil(t, err)
+	assert.Equal(t, l, Level(math.MinInt8))
+
+	_, err = cast.To[Level]("-1000", cast.Weak())
+	assert.Error(t, err, "value -1000 overflows int8")
 }
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ToStringMap casts an any to a map[string]any.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMap(i any) map[string]any {
+	v, _ := ToStringMapE(i)
+	return v
+}
+
+// ToStringMapE casts an any to a map[string]any. Keys of a map are cast
+// by ToString, and a struct contributes its fields named as the json tag
+// says. Values are kept as they are.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapE(i any) (map[string]any, error) {
+	switch m := i.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return m, nil
+	case map[string]string:
+		r := make(map[string]any, len(m))
+		for k, v := range m {
+			r[k] = v
+		}
+		return r, nil
+	}
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return map[string]any{}, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		r := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			r[ToString(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		return r, nil
+	case reflect.Struct:
+		fields := cachedTypeFields(v.Type())
+		r := make(map[string]any, len(fields.list))
+		for _, f := range fields.list {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok {
+				continue
+			}
+			r[f.name] = fv.Interface()
+		}
+		return r, nil
+	default:
+		return nil, unsupportedTypeError(i, "map[string]any")
+	}
+}
+
+// ToStringMapString casts an any to a map[string]string.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapString(i any) map[string]string {
+	v, _ := ToStringMapStringE(i)
+	return v
+}
+
+// ToStringMapStringE casts an any to a map[string]string, the keys are
+// resolved like ToStringMapE does and the values are cast by ToString.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapStringE(i any) (map[string]string, error) {
+	if m, ok := i.(map[string]string); ok {
+		return m, nil
+	}
+	m, err := ToStringMapE(i)
+	if err != nil {
+		return nil, unsupportedTypeError(i, "map[string]string")
+	}
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = ToString(v)
+	}
+	return r, nil
+}
+
+// ToStringMapInt casts an any to a map[string]int.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapInt(i any) map[string]int {
+	v, _ := ToStringMapIntE(i)
+	return v
+}
+
+// ToStringMapIntE casts an any to a map[string]int, the keys and the values
+// are cast like ToMap does, and an error names the key of the bad value.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapIntE(i any) (map[string]int, error) {
+	if m, ok := i.(map[string]int); ok {
+		return m, nil
+	}
+	return ToMap[string, int](i)
+}
+
+// ToStringMapInt64 casts an any to a map[string]int64.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapInt64(i any) map[string]int64 {
+	v, _ := ToStringMapInt64E(i)
+	return v
+}
+
+// ToStringMapInt64E casts an any to a map[string]int64, the keys and the
+// values are cast like ToMap does, and an error names the key of the bad
+// value.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapInt64E(i any) (map[string]int64, error) {
+	if m, ok := i.(map[string]int64); ok {
+		return m, nil
+	}
+	return ToMap[string, int64](i)
+}
+
+// ToStringMapBool casts an any to a map[string]bool.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapBool(i any) map[string]bool {
+	v, _ := ToStringMapBoolE(i)
+	return v
+}
+
+// ToStringMapBoolE casts an any to a map[string]bool, the keys and the
+// values are cast like ToMap does, and an error names the key of the bad
+// value.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringMapBoolE(i any) (map[string]bool, error) {
+	if m, ok := i.(map[string]bool); ok {
+		return m, nil
+	}
+	return ToMap[string, bool](i)
+}
+
+// StructToMap converts the struct i, or the struct i points to, into a
+// map[string]any by FAST, so that nested structs become maps too and the
+// fields are named by the json tags, or the TagKey tags when given. A nil
+// pointer gives an empty map.
+func StructToMap(i any, opts ...Option) (map[string]any, error) {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return map[string]any{}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, unsupportedTypeError(i, "map[string]any")
+	}
+	var m map[string]any
+	if err := FAST.Convert(i, &m, opts...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ToSliceOfStringMaps casts a slice or an array of maps or structs, like
+// the rows of a decoded JSON array or of a SQL result set, to a
+// []map[string]any. A map element is cast by ToStringMapE and a struct
+// element, or a pointer to one, is converted by StructToMap. A nil
+// element gives a nil map.
+func ToSliceOfStringMaps(i any) ([]map[string]any, error) {
+	switch s := i.(type) {
+	case nil:
+		return nil, nil
+	case []map[string]any:
+		return s, nil
+	}
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, unsupportedTypeError(i, "[]map[string]any")
+	}
+	r := make([]map[string]any, v.Len())
+	for j := range r {
+		e := v.Index(j)
+		if e.Kind() == reflect.Interface {
+			e = e.Elem()
+		}
+		if !e.IsValid() || (e.Kind() == reflect.Pointer && e.IsNil()) {
+			continue
+		}
+		var (
+			m   map[string]any
+			err error
+		)
+		switch x := e.Interface(); reflect.Indirect(e).Kind() {
+		case reflect.Struct:
+			m, err = StructToMap(x)
+		default:
+			m, err = ToStringMapE(x)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert element %d: %w", j, err)
+		}
+		r[j] = m
+	}
+	return r, nil
+}
+
+// MapToStruct converts m into a T by FAST, the keys of m are matched with
+// the field names as FAST.Convert does, so CaseInsensitive and TagKey are
+// honored. Values are cast to the types of the fields.
+func MapToStruct[T any](m map[string]any, opts ...Option) (T, error) {
+	var t T
+	err := FAST.Convert(m, &t, opts...)
+	return t, err
+}
+
+// fieldByIndex returns the nested field of v by index, it returns false
+// when an embedded pointer on the way is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToStringMap(t *testing.T) {
+
+	assert.Equal(t, cast.ToStringMap(nil), map[string]any{})
+
+	m := map[string]any{"a": 1}
+	assert.Equal(t, cast.ToStringMap(m), m)
+	assert.Equal(t, cast.ToStringMap(map[string]string{"a": "1"}), map[string]any{"a": "1"})
+	assert.Equal(t, cast.ToStringMap(map[int]bool{1: true}), map[string]any{"1": true})
+	assert.Equal(t, cast.ToStringMap(map[any]any{"a": 1, 2: "b"}), map[string]any{"a": 1, "2": "b"})
+
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Stu struct {
+		*Base
+		Name  string `json:"name"`
+		Age   int
+		Skip  string `json:"-"`
+		inner string
+	}
+	stu := Stu{Base: &Base{ID: 1}, Name: "a", Age: 3}
+	assert.Equal(t, cast.ToStringMap(stu), map[string]any{"id": 1, "name": "a", "Age": 3})
+	assert.Equal(t, cast.ToStringMap(&stu), map[string]any{"id": 1, "name": "a", "Age": 3})
+	assert.Equal(t, cast.ToStringMap(Stu{Name: "a"}), map[string]any{"name": "a", "Age": 0})
+
+	_, err := cast.ToStringMapE(3)
+	assert.Error(t, err, "unable to cast type \\(int\\) to map\\[string\\]any")
+}
+
+func TestToStringMapString(t *testing.T) {
+
+	assert.Equal(t, cast.ToStringMapString(nil), map[string]string{})
+
+	m := map[string]string{"a": "1"}
+	assert.Equal(t, cast.ToStringMapString(m), m)
+	assert.Equal(t, cast.ToStringMapString(map[string]any{"a": 1, "b": true}), map[string]string{"a": "1", "b": "true"})
+	assert.Equal(t, cast.ToStringMapString(map[int]float64{1: 1.5}), map[string]string{"1": "1.5"})
+
+	type Stu struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	assert.Equal(t, cast.ToStringMapString(Stu{Name: "a", Age: 3}), map[string]string{"name": "a", "age": "3"})
+
+	_, err := cast.ToStringMapStringE([]int{1})
+	assert.Error(t, err, "unable to cast type \\(\\[\\]int\\) to map\\[string\\]string")
+}
+
+func TestToStringMapInt(t *testing.T) {
+
+	assert.Equal(t, cast.ToStringMapInt(nil), map[string]int{})
+
+	m := map[string]int{"a": 1}
+	assert.Equal(t, cast.ToStringMapInt(m), m)
+	assert.Equal(t, cast.ToStringMapInt(map[string]any{"a": "1", "b": 2.0}), map[string]int{"a": 1, "b": 2})
+	assert.Equal(t, cast.ToStringMapInt(map[string]string{"a": "0x10"}), map[string]int{"a": 16})
+	assert.Equal(t, cast.ToStringMapInt64(map[int]uint8{1: 2}), map[string]int64{"1": 2})
+
+	type Limits struct {
+		Max int `json:"max"`
+	}
+	assert.Equal(t, cast.ToStringMapInt64(Limits{Max: 3}), map[string]int64{"max": 3})
+
+	_, err := cast.ToStringMapIntE(map[string]any{"a": "abc"})
+	assert.Error(t, err, "unable to cast value of key a: strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	_, err = cast.ToStringMapInt64E([]int{1})
+	assert.Error(t, err, "unable to cast type \\(\\[\\]int\\) to map")
+}
+
+func TestToStringMapBool(t *testing.T) {
+
+	assert.Equal(t, cast.ToStringMapBool(nil), map[string]bool{})
+
+	m := map[string]bool{"a": true}
+	assert.Equal(t, cast.ToStringMapBool(m), m)
+	assert.Equal(t, cast.ToStringMapBool(map[string]any{"a": "on", "b": 0}), map[string]bool{"a": true, "b": false})
+	assert.Equal(t, cast.ToStringMapBool(map[string]string{"a": "false"}), map[string]bool{"a": false})
+
+	_, err := cast.ToStringMapBoolE(map[string]any{"flag": "maybe"})
+	assert.Error(t, err, "unable to cast value of key flag: unable to cast \"maybe\" to bool")
+}
+
+func TestStructToMap(t *testing.T) {
+
+	type Address struct {
+		City string `json:"city" cfg:"town"`
+	}
+	type User struct {
+		Name    string   `json:"name"`
+		Age     int      `json:"age,omitempty"`
+		Tags    []string `json:"tags"`
+		Address *Address `json:"address"`
+	}
+
+	u := User{Name: "a", Tags: []string{"x"}, Address: &Address{City: "b"}}
+	m, err := cast.StructToMap(u)
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]any{
+		"name":    "a",
+		"tags":    []interface{}{"x"},
+		"address": map[string]interface{}{"city": "b"},
+	})
+
+	m, err = cast.StructToMap(&u, cast.TagKey("cfg"))
+	assert.Nil(t, err)
+	assert.Equal(t, m["address"], map[string]interface{}{"town": "b"})
+
+	m, err = cast.StructToMap((*User)(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]any{})
+
+	_, err = cast.StructToMap(map[string]int{})
+	assert.Error(t, err, "unable to cast type \\(map\\[string\\]int\\) to map\\[string\\]any")
+}
+
+func TestMapToStruct(t *testing.T) {
+
+	type User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	u, err := cast.MapToStruct[User](map[string]any{"name": "a", "age": "3"})
+	assert.Nil(t, err)
+	assert.Equal(t, u, User{Name: "a", Age: 3})
+
+	p, err := cast.MapToStruct[*User](map[string]any{"NAME": "b"}, cast.CaseInsensitive())
+	assert.Nil(t, err)
+	assert.Equal(t, p, &User{Name: "b"})
+
+	_, err = cast.MapToStruct[User](map[string]any{"age": "abc"})
+	assert.Error(t, err, "invalid syntax")
+}
+
+func TestToSliceOfStringMaps(t *testing.T) {
+
+	type Row struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	m, err := cast.ToSliceOfStringMaps(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+
+	m, err = cast.ToSliceOfStringMaps([]Row{{1, "a"}, {2, "b"}})
+	assert.Nil(t, err)
+	assert.Equal(t, m, []map[string]any{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+	})
+
+	m, err = cast.ToSliceOfStringMaps([]any{
+		map[string]any{"id": 1},
+		map[int]string{1: "x"},
+		&Row{ID: 3},
+		nil,
+		(*Row)(nil),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, m, []map[string]any{
+		{"id": 1},
+		{"1": "x"},
+		{"id": 3, "name": ""},
+		nil,
+		nil,
+	})
+
+	m, err = cast.ToSliceOfStringMaps([1]map[string]string{{"a": "b"}})
+	assert.Nil(t, err)
+	assert.Equal(t, m, []map[string]any{{"a": "b"}})
+
+	_, err = cast.ToSliceOfStringMaps([]any{map[string]any{}, 1})
+	assert.Error(t, err, "unable to convert element 1: unable to cast type \\(int\\) to map\\[string\\]any")
+
+	_, err = cast.ToSliceOfStringMaps("abc")
+	assert.Error(t, err, "unable to cast type \\(string\\) to \\[\\]map\\[string\\]any")
+}
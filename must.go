@@ -0,0 +1,83 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"fmt"
+	"time"
+)
+
+// MustTo casts an any to a T, it panics when the cast fails.
+func MustTo[T any](i any, opts ...Option) T {
+	v, err := To[T](i, opts...)
+	return must(v, err, i)
+}
+
+// MustToBool casts an any to a bool, it panics when the cast fails.
+func MustToBool(i any, opts ...Option) bool {
+	v, err := ToBoolE(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToInt casts an any to an int, it panics when the cast fails.
+func MustToInt(i any, opts ...Option) int {
+	v, err := ToIntE(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToInt64 casts an any to an int64, it panics when the cast fails.
+func MustToInt64(i any, opts ...Option) int64 {
+	v, err := ToInt64E(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToUint casts an any to an uint, it panics when the cast fails.
+func MustToUint(i any, opts ...Option) uint {
+	v, err := ToUintE(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToUint64 casts an any to an uint64, it panics when the cast fails.
+func MustToUint64(i any, opts ...Option) uint64 {
+	v, err := ToUint64E(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToFloat64 casts an any to a float64, it panics when the cast fails.
+func MustToFloat64(i any, opts ...Option) float64 {
+	v, err := ToFloat64E(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToDuration casts an any to a time.Duration, it panics when the cast fails.
+func MustToDuration(i any, opts ...Option) time.Duration {
+	v, err := ToDurationE(i, opts...)
+	return must(v, err, i)
+}
+
+// MustToTime casts an any to a time.Time, it panics when the cast fails.
+func MustToTime(i any, opts ...Option) time.Time {
+	v, err := ToTimeE(i, opts...)
+	return must(v, err, i)
+}
+
+func must[T any](v T, err error, i any) T {
+	if err != nil {
+		panic(fmt.Errorf("unable to cast (%T) %v: %w", i, i, err))
+	}
+	return v
+}
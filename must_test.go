@@ -0,0 +1,57 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func mustPanic(t *testing.T, f func(), expr string) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if r == nil {
+			t.Error("expect panic")
+			return
+		}
+		assert.Error(t, r.(error), expr)
+	}()
+	f()
+}
+
+func TestMustTo(t *testing.T) {
+
+	assert.Equal(t, cast.MustTo[int]("3"), 3)
+	assert.Equal(t, cast.MustToBool("yes"), true)
+	assert.Equal(t, cast.MustToInt("3"), 3)
+	assert.Equal(t, cast.MustToInt64("3"), int64(3))
+	assert.Equal(t, cast.MustToUint("3"), uint(3))
+	assert.Equal(t, cast.MustToUint64("3"), uint64(3))
+	assert.Equal(t, cast.MustToFloat64("1.5"), 1.5)
+	assert.Equal(t, cast.MustToDuration("3s"), 3*time.Second)
+	assert.Equal(t, cast.MustToTime(3), time.Unix(0, 3))
+
+	mustPanic(t, func() { cast.MustTo[int8](300) }, "unable to cast \\(int\\) 300: value 300 overflows int8")
+	mustPanic(t, func() { cast.MustToInt("abc") }, "unable to cast \\(string\\) abc: strconv.ParseInt")
+	mustPanic(t, func() { cast.MustToUint64(-1) }, "unable to cast \\(int\\) -1: unable to cast negative value -1 to uint64")
+	mustPanic(t, func() { cast.MustToTime(true) }, "unable to cast \\(bool\\) true: unable to cast type \\(bool\\) to Time")
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"reflect"
+	"time"
+)
+
+// ToBoolPtrE casts an any to a *bool, it returns nil for an absent i, that
+// is nil, a nil pointer, an empty string or an empty []byte, so that an
+// absent value is told apart from the zero value.
+func ToBoolPtrE(i any, opts ...Option) (*bool, error) {
+	return toPtrE(i, ToBoolE, opts...)
+}
+
+// ToIntPtrE casts an any to an *int, it returns nil for an absent i.
+func ToIntPtrE(i any, opts ...Option) (*int, error) {
+	return toPtrE(i, ToIntE, opts...)
+}
+
+// ToInt64PtrE casts an any to an *int64, it returns nil for an absent i.
+func ToInt64PtrE(i any, opts ...Option) (*int64, error) {
+	return toPtrE(i, ToInt64E, opts...)
+}
+
+// ToUintPtrE casts an any to an *uint, it returns nil for an absent i.
+func ToUintPtrE(i any, opts ...Option) (*uint, error) {
+	return toPtrE(i, ToUintE, opts...)
+}
+
+// ToUint64PtrE casts an any to an *uint64, it returns nil for an absent i.
+func ToUint64PtrE(i any, opts ...Option) (*uint64, error) {
+	return toPtrE(i, ToUint64E, opts...)
+}
+
+// ToFloat64PtrE casts an any to a *float64, it returns nil for an absent i.
+func ToFloat64PtrE(i any, opts ...Option) (*float64, error) {
+	return toPtrE(i, ToFloat64E, opts...)
+}
+
+// ToStringPtrE casts an any to a *string, it returns nil for an absent i.
+// The options are those of ToStringWith.
+func ToStringPtrE(i any, opts ...Option) (*string, error) {
+	return toPtrE(i, func(i any, opts ...Option) (string, error) {
+		s, err := ToStringE(i)
+		if err == nil && len(opts) > 0 {
+			s = ToStringWith(i, opts...)
+		}
+		return s, err
+	}, opts...)
+}
+
+// ToDurationPtrE casts an any to a *time.Duration, it returns nil for an
+// absent i.
+func ToDurationPtrE(i any, opts ...Option) (*time.Duration, error) {
+	return toPtrE(i, ToDurationE, opts...)
+}
+
+// ToTimePtrE casts an any to a *time.Time, it returns nil for an absent i.
+func ToTimePtrE(i any, opts ...Option) (*time.Time, error) {
+	return toPtrE(i, ToTimeE, opts...)
+}
+
+func toPtrE[T any](i any, cast func(any, ...Option) (T, error), opts ...Option) (*T, error) {
+	if absent(i) {
+		return nil, nil
+	}
+	v, err := cast(i, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// absent reports whether i is nil, a nil pointer, an empty string or an
+// empty []byte.
+func absent(i any) bool {
+	switch s := i.(type) {
+	case nil:
+		return true
+	case string:
+		return s == ""
+	case []byte:
+		return len(s) == 0
+	}
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Pointer {
+		return false
+	}
+	if v.IsNil() {
+		return true
+	}
+	return absent(v.Elem().Interface())
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToPtrE(t *testing.T) {
+
+	for _, i := range []any{nil, "", []byte{}, (*int)(nil), cast.StringPtr("")} {
+		p, err := cast.ToIntPtrE(i)
+		assert.Nil(t, err)
+		assert.Nil(t, p)
+	}
+
+	i, err := cast.ToIntPtrE("0")
+	assert.Nil(t, err)
+	assert.Equal(t, *i, 0)
+
+	i64, err := cast.ToInt64PtrE(cast.StringPtr("42"))
+	assert.Nil(t, err)
+	assert.Equal(t, *i64, int64(42))
+
+	u, err := cast.ToUintPtrE(3)
+	assert.Nil(t, err)
+	assert.Equal(t, *u, uint(3))
+
+	u64, err := cast.ToUint64PtrE("0x10", cast.Base(0))
+	assert.Nil(t, err)
+	assert.Equal(t, *u64, uint64(16))
+
+	f, err := cast.ToFloat64PtrE("1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, *f, 1.5)
+
+	b, err := cast.ToBoolPtrE("false")
+	assert.Nil(t, err)
+	assert.False(t, *b)
+
+	b, err = cast.ToBoolPtrE("")
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+
+	s, err := cast.ToStringPtrE(0)
+	assert.Nil(t, err)
+	assert.Equal(t, *s, "0")
+
+	s, err = cast.ToStringPtrE(" a ", cast.TrimSpace())
+	assert.Nil(t, err)
+	assert.Equal(t, *s, "a")
+
+	d, err := cast.ToDurationPtrE("1s")
+	assert.Nil(t, err)
+	assert.Equal(t, *d, time.Second)
+
+	tm, err := cast.ToTimePtrE("2023-01-02", cast.TimeFormat("2006-01-02"))
+	assert.Nil(t, err)
+	assert.True(t, tm.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	tm, err = cast.ToTimePtrE((*time.Time)(nil))
+	assert.Nil(t, err)
+	assert.Nil(t, tm)
+
+	_, err = cast.ToIntPtrE("abc")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	_, err = cast.ToStringPtrE(make(chan int))
+	assert.Error(t, err, "unable to cast type \\(chan int\\) to string")
+}
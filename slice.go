@@ -0,0 +1,196 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ToStringSlice casts an any to a []string.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringSlice(i any) []string {
+	v, _ := ToStringSliceE(i)
+	return v
+}
+
+// ToStringSliceE casts an any to a []string. Each element of a slice or
+// an array is cast by ToString, a string or a []byte is split around
+// white space, and any other scalar is wrapped into a one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToStringSliceE(i any) ([]string, error) {
+	switch s := i.(type) {
+	case nil:
+		return []string{}, nil
+	case []string:
+		return s, nil
+	case []interface{}:
+		r := make([]string, len(s))
+		for j, e := range s {
+			r[j] = ToString(e)
+		}
+		return r, nil
+	case string:
+		return strings.Fields(s), nil
+	case []byte:
+		return strings.Fields(string(s)), nil
+	}
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		r := make([]string, v.Len())
+		for j := range r {
+			r[j] = ToString(v.Index(j).Interface())
+		}
+		return r, nil
+	case reflect.Map, reflect.Chan, reflect.Func:
+		return nil, unsupportedTypeError(i, "[]string")
+	default:
+		return []string{ToString(i)}, nil
+	}
+}
+
+// ToAnySlice casts an any to a []interface{}. Each element of a slice or
+// an array is boxed as it is, without being converted, a scalar is wrapped
+// into a one-element slice, and nil gives an empty slice.
+func ToAnySlice(i any) []interface{} {
+	switch s := i.(type) {
+	case nil:
+		return []interface{}{}
+	case []interface{}:
+		return s
+	}
+	v := reflect.ValueOf(i)
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		return []interface{}{i}
+	}
+	r := make([]interface{}, v.Len())
+	for j := range r {
+		r[j] = v.Index(j).Interface()
+	}
+	return r
+}
+
+// ToBoolSlice casts an any to a []bool.
+// When type is clear, it is recommended to use standard library functions.
+func ToBoolSlice(i any, opts ...Option) []bool {
+	v, _ := ToBoolSliceE(i, opts...)
+	return v
+}
+
+// ToBoolSliceE casts an any to a []bool. Each element of a slice or an
+// array is cast by ToBoolE with opts, and a scalar is wrapped into a
+// one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToBoolSliceE(i any, opts ...Option) ([]bool, error) {
+	if s, ok := i.([]bool); ok {
+		return s, nil
+	}
+	return ToSlice[bool](i, opts...)
+}
+
+// ToUintSlice casts an any to a []uint.
+// When type is clear, it is recommended to use standard library functions.
+func ToUintSlice(i any, opts ...Option) []uint {
+	v, _ := ToUintSliceE(i, opts...)
+	return v
+}
+
+// ToUintSliceE casts an any to a []uint. Each element of a slice or an
+// array is cast by ToUintE with opts, so a negative element is an error,
+// and a scalar is wrapped into a one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToUintSliceE(i any, opts ...Option) ([]uint, error) {
+	if s, ok := i.([]uint); ok {
+		return s, nil
+	}
+	return ToSlice[uint](i, opts...)
+}
+
+// ToUint64Slice casts an any to a []uint64.
+// When type is clear, it is recommended to use standard library functions.
+func ToUint64Slice(i any, opts ...Option) []uint64 {
+	v, _ := ToUint64SliceE(i, opts...)
+	return v
+}
+
+// ToUint64SliceE casts an any to a []uint64. Each element of a slice or
+// an array is cast by ToUint64E with opts, so a negative element is an
+// error, and a scalar is wrapped into a one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToUint64SliceE(i any, opts ...Option) ([]uint64, error) {
+	if s, ok := i.([]uint64); ok {
+		return s, nil
+	}
+	return ToSlice[uint64](i, opts...)
+}
+
+// ToFloat64Slice casts an any to a []float64.
+// When type is clear, it is recommended to use standard library functions.
+func ToFloat64Slice(i any, opts ...Option) []float64 {
+	v, _ := ToFloat64SliceE(i, opts...)
+	return v
+}
+
+// ToFloat64SliceE casts an any to a []float64. Each element of a slice or
+// an array is cast by ToFloat64E with opts, and a scalar is wrapped into
+// a one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToFloat64SliceE(i any, opts ...Option) ([]float64, error) {
+	if s, ok := i.([]float64); ok {
+		return s, nil
+	}
+	return ToSlice[float64](i, opts...)
+}
+
+// ToDurationSlice casts an any to a []time.Duration.
+// When type is clear, it is recommended to use standard library functions.
+func ToDurationSlice(i any, opts ...Option) []time.Duration {
+	v, _ := ToDurationSliceE(i, opts...)
+	return v
+}
+
+// ToDurationSliceE casts an any to a []time.Duration. Each element of a
+// slice or an array is cast by ToDurationE with opts, so a unit given by
+// TimeFormat applies to every number, and a scalar is wrapped into a
+// one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToDurationSliceE(i any, opts ...Option) ([]time.Duration, error) {
+	if s, ok := i.([]time.Duration); ok {
+		return s, nil
+	}
+	return ToSlice[time.Duration](i, opts...)
+}
+
+// ToTimeSlice casts an any to a []time.Time.
+// When type is clear, it is recommended to use standard library functions.
+func ToTimeSlice(i any, opts ...Option) []time.Time {
+	v, _ := ToTimeSliceE(i, opts...)
+	return v
+}
+
+// ToTimeSliceE casts an any to a []time.Time. Each element of a slice or
+// an array, such as epoch integers or formatted strings, is cast by
+// ToTimeE with opts, and a scalar is wrapped into a one-element slice.
+// When type is clear, it is recommended to use standard library functions.
+func ToTimeSliceE(i any, opts ...Option) ([]time.Time, error) {
+	if s, ok := i.([]time.Time); ok {
+		return s, nil
+	}
+	return ToSlice[time.Time](i, opts...)
+}
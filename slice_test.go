@@ -0,0 +1,147 @@
+/*
+ * Copyright 2023 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lvan100/cast"
+	"github.com/lvan100/cast/internal/assert"
+)
+
+func TestToStringSlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToStringSlice(nil), []string{})
+
+	assert.Equal(t, cast.ToStringSlice([]string{"a", "b"}), []string{"a", "b"})
+	assert.Equal(t, cast.ToStringSlice([]interface{}{1, "a", true}), []string{"1", "a", "true"})
+	assert.Equal(t, cast.ToStringSlice([]int{1, 2}), []string{"1", "2"})
+	assert.Equal(t, cast.ToStringSlice([2]float64{1.5, 2}), []string{"1.5", "2"})
+
+	assert.Equal(t, cast.ToStringSlice("a b  c"), []string{"a", "b", "c"})
+	assert.Equal(t, cast.ToStringSlice([]byte("a b\tc")), []string{"a", "b", "c"})
+	assert.Equal(t, cast.ToStringSlice([]byte(nil)), []string{})
+	assert.Equal(t, cast.ToStringSlice(3), []string{"3"})
+	assert.Equal(t, cast.ToStringSlice(cast.BoolPtr(true)), []string{"true"})
+
+	_, err := cast.ToStringSliceE(map[string]int{})
+	assert.Error(t, err, "unable to cast type \\(map\\[string\\]int\\) to \\[\\]string")
+}
+
+func TestToAnySlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToAnySlice(nil), []interface{}{})
+	assert.Equal(t, cast.ToAnySlice([]int(nil)), []interface{}{})
+
+	assert.Equal(t, cast.ToAnySlice([]interface{}{1, "a"}), []interface{}{1, "a"})
+	assert.Equal(t, cast.ToAnySlice([]int{1, 2}), []interface{}{1, 2})
+	assert.Equal(t, cast.ToAnySlice([2]string{"a", "b"}), []interface{}{"a", "b"})
+	assert.Equal(t, cast.ToAnySlice([][]int{{1}}), []interface{}{[]int{1}})
+	assert.Equal(t, cast.ToAnySlice("abc"), []interface{}{"abc"})
+	assert.Equal(t, cast.ToAnySlice(map[string]int{"a": 1}), []interface{}{map[string]int{"a": 1}})
+}
+
+func TestToBoolSlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToBoolSlice(nil), []bool{})
+
+	assert.Equal(t, cast.ToBoolSlice([]bool{true, false}), []bool{true, false})
+	assert.Equal(t, cast.ToBoolSlice([]string{"yes", "0", "off"}), []bool{true, false, false})
+	assert.Equal(t, cast.ToBoolSlice([]interface{}{1, "true", false}), []bool{true, true, false})
+	assert.Equal(t, cast.ToBoolSlice("on"), []bool{true})
+
+	_, err := cast.ToBoolSliceE([]string{"true", "abc"})
+	assert.Error(t, err, "unable to cast element 1: unable to cast \"abc\" to bool")
+
+	assert.Equal(t, cast.ToBoolSlice([]string{"2", "off", "0"}, cast.NumericBool()), []bool{true, false, false})
+	assert.Equal(t, cast.ToBoolSlice([]string{" "}, cast.EmptyAsZero()), []bool{false})
+
+	_, err = cast.ToBoolSliceE([]string{"2"})
+	assert.Error(t, err, "unable to cast element 0: unable to cast \"2\" to bool")
+}
+
+func TestToUintSlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToUintSlice(nil), []uint{})
+
+	assert.Equal(t, cast.ToUintSlice([]uint{1, 2}), []uint{1, 2})
+	assert.Equal(t, cast.ToUintSlice([]int{1, 2}), []uint{1, 2})
+	assert.Equal(t, cast.ToUintSlice([]string{"1", "0x10"}), []uint{1, 16})
+	assert.Equal(t, cast.ToUintSlice([]interface{}{1, "2", 3.0}), []uint{1, 2, 3})
+	assert.Equal(t, cast.ToUintSlice("7"), []uint{7})
+
+	assert.Equal(t, cast.ToUint64Slice([]uint64{1, 2}), []uint64{1, 2})
+	assert.Equal(t, cast.ToUint64Slice([2]int8{1, 2}), []uint64{1, 2})
+	assert.Equal(t, cast.ToUint64Slice([]string{"010", "1_0"}, cast.Base(10), cast.StripUnderscores()), []uint64{10, 10})
+
+	_, err := cast.ToUintSliceE([]int{1, -2})
+	assert.Error(t, err, "unable to cast element 1: unable to cast negative value -2 to uint$")
+
+	_, err = cast.ToUint64SliceE([]string{"1", "abc"})
+	assert.Error(t, err, "unable to cast element 1: strconv.ParseUint: parsing \"abc\": invalid syntax")
+}
+
+func TestToFloat64Slice(t *testing.T) {
+
+	assert.Equal(t, cast.ToFloat64Slice(nil), []float64{})
+
+	assert.Equal(t, cast.ToFloat64Slice([]float64{1.5, 2}), []float64{1.5, 2})
+	assert.Equal(t, cast.ToFloat64Slice([]float32{1.5, 2}), []float64{1.5, 2})
+	assert.Equal(t, cast.ToFloat64Slice([]string{"1.5", "-2"}), []float64{1.5, -2})
+	assert.Equal(t, cast.ToFloat64Slice([]interface{}{1, "2.5", true}), []float64{1, 2.5, 1})
+	assert.Equal(t, cast.ToFloat64Slice("3.5"), []float64{3.5})
+
+	_, err := cast.ToFloat64SliceE([]string{"1", "abc"})
+	assert.Error(t, err, "unable to cast element 1: strconv.ParseFloat: parsing \"abc\": invalid syntax")
+
+	_, err = cast.ToFloat64SliceE([]string{"1", "NaN"}, cast.RejectNonFinite())
+	assert.Error(t, err, "unable to cast element 1: value NaN is not finite")
+}
+
+func TestToDurationSlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToDurationSlice(nil), []time.Duration{})
+
+	s := []time.Duration{time.Second, time.Minute}
+	assert.Equal(t, cast.ToDurationSlice(s), s)
+	assert.Equal(t, cast.ToDurationSlice([]string{"1s", "1m"}), s)
+	assert.Equal(t, cast.ToDurationSlice([]interface{}{"1s", time.Minute}), s)
+	assert.Equal(t, cast.ToDurationSlice([]int{1, 60}, cast.TimeFormat("s")), s)
+	assert.Equal(t, cast.ToDurationSlice([]string{"1", "60"}, cast.TimeFormat("s")), s)
+	assert.Equal(t, cast.ToDurationSlice("1s"), []time.Duration{time.Second})
+
+	_, err := cast.ToDurationSliceE([]string{"1s", "abc"})
+	assert.Error(t, err, "unable to cast element 1: time: invalid duration")
+}
+
+func TestToTimeSlice(t *testing.T) {
+
+	assert.Equal(t, cast.ToTimeSlice(nil), []time.Time{})
+
+	t1, t2 := time.Unix(1, 0), time.Unix(2, 0)
+	s := []time.Time{t1, t2}
+	assert.Equal(t, cast.ToTimeSlice(s), s)
+	assert.Equal(t, cast.ToTimeSlice([]int64{1, 2}, cast.TimeFormat("s")), s)
+	assert.Equal(t, cast.ToTimeSlice([]interface{}{1000, "2s"}, cast.TimeFormat("ms")), s)
+
+	r := cast.ToTimeSlice([]string{"1970-01-01T00:00:01Z", "1970-01-01T00:00:02Z"}, cast.TimeFormat(time.RFC3339))
+	assert.True(t, r[0].Equal(t1) && r[1].Equal(t2))
+
+	_, err := cast.ToTimeSliceE([]string{"1970-01-01T00:00:01Z", "abc"}, cast.TimeFormat(time.RFC3339))
+	assert.Error(t, err, "unable to cast element 1: parsing time \"abc\"")
+}
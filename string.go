@@ -17,15 +17,21 @@
 package cast
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
-// ToString casts an any to a string.
+// ToString casts an any to a string. Composite values such as maps,
+// slices and structs are rendered as canonical json with sorted map keys,
+// non-string map keys are cast by ToString first. Nil pointers, maps,
+// slices, funcs and channels are all rendered as "", even when their type
+// has a String method.
 // When type is clear, it is recommended to use standard library functions.
 func ToString(i any) string {
 	switch s := i.(type) {
@@ -41,16 +47,6 @@ func ToString(i any) string {
 		return strconv.Itoa(int(s))
 	case int64:
 		return strconv.FormatInt(s, 10)
-	case *int:
-		return strconv.Itoa(*s)
-	case *int8:
-		return strconv.FormatInt(int64(*s), 10)
-	case *int16:
-		return strconv.FormatInt(int64(*s), 10)
-	case *int32:
-		return strconv.Itoa(int(*s))
-	case *int64:
-		return strconv.FormatInt(*s, 10)
 	case uint:
 		return strconv.FormatUint(uint64(s), 10)
 	case uint8:
@@ -61,32 +57,16 @@ func ToString(i any) string {
 		return strconv.FormatUint(uint64(s), 10)
 	case uint64:
 		return strconv.FormatUint(s, 10)
-	case *uint:
-		return strconv.FormatUint(uint64(*s), 10)
-	case *uint8:
-		return strconv.FormatUint(uint64(*s), 10)
-	case *uint16:
-		return strconv.FormatUint(uint64(*s), 10)
-	case *uint32:
-		return strconv.FormatUint(uint64(*s), 10)
-	case *uint64:
-		return strconv.FormatUint(*s, 10)
+	case uintptr:
+		return strconv.FormatUint(uint64(s), 10)
 	case float32:
 		return strconv.FormatFloat(float64(s), 'f', -1, 32)
 	case float64:
 		return strconv.FormatFloat(s, 'f', -1, 64)
-	case *float32:
-		return strconv.FormatFloat(float64(*s), 'f', -1, 32)
-	case *float64:
-		return strconv.FormatFloat(*s, 'f', -1, 64)
 	case string:
 		return s
-	case *string:
-		return *s
 	case bool:
 		return strconv.FormatBool(s)
-	case *bool:
-		return strconv.FormatBool(*s)
 	case []byte:
 		return string(s)
 	case template.HTML:
@@ -99,10 +79,48 @@ func ToString(i any) string {
 		return string(s)
 	case template.HTMLAttr:
 		return string(s)
+	case time.Duration:
+		return s.String()
+	}
+	// The cases below dereference pointers, and a nil one has no string
+	// form even when its type has a String method.
+	if isNilPointer(i) {
+		return ""
+	}
+	switch s := i.(type) {
+	case *int:
+		return strconv.Itoa(*s)
+	case *int8:
+		return strconv.FormatInt(int64(*s), 10)
+	case *int16:
+		return strconv.FormatInt(int64(*s), 10)
+	case *int32:
+		return strconv.Itoa(int(*s))
+	case *int64:
+		return strconv.FormatInt(*s, 10)
+	case *uint:
+		return strconv.FormatUint(uint64(*s), 10)
+	case *uint8:
+		return strconv.FormatUint(uint64(*s), 10)
+	case *uint16:
+		return strconv.FormatUint(uint64(*s), 10)
+	case *uint32:
+		return strconv.FormatUint(uint64(*s), 10)
+	case *uint64:
+		return strconv.FormatUint(*s, 10)
+	case *uintptr:
+		return strconv.FormatUint(uint64(*s), 10)
+	case *float32:
+		return strconv.FormatFloat(float64(*s), 'f', -1, 32)
+	case *float64:
+		return strconv.FormatFloat(*s, 'f', -1, 64)
+	case *string:
+		return *s
+	case *bool:
+		return strconv.FormatBool(*s)
 	case *time.Time:
-		if s == nil {
-			return ""
-		}
+		return s.String()
+	case *time.Duration:
 		return s.String()
 	case fmt.Stringer:
 		return s.String()
@@ -125,9 +143,215 @@ func ToString(i any) string {
 			return rv.String()
 		}
 
+		// The type switch misses a String or an Error method declared on
+		// the pointer receiver, so try them on a copy of the value.
+		if pt := reflect.PointerTo(rv.Type()); pt.Implements(stringerType) || pt.Implements(errorType) {
+			pv := reflect.New(rv.Type())
+			pv.Elem().Set(rv)
+			return ToString(pv.Interface())
+		}
+
 		if jb, err := json.Marshal(s); err == nil {
 			return string(jb)
 		}
+		if jb, err := json.Marshal(jsonValue(rv)); err == nil {
+			return string(jb)
+		}
 		return fmt.Sprint(s)
 	}
 }
+
+// ToStringE casts an any to a string like ToString, but returns an error
+// instead of a fmt.Sprint rendering for the values that have no clean
+// string form: non-nil channels, funcs and unsafe pointers, complex
+// numbers, and composite values that json can't encode, like a map
+// holding a func. Values with a String or an Error method never fail.
+func ToStringE(i any) (string, error) {
+	if i == nil {
+		return "", nil
+	}
+	switch i.(type) {
+	case fmt.Stringer, error:
+		return ToString(i), nil
+	}
+	rv := reflect.ValueOf(i)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	if pt := reflect.PointerTo(rv.Type()); pt.Implements(stringerType) || pt.Implements(errorType) {
+		return ToString(i), nil
+	}
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if rv.IsNil() {
+			return "", nil
+		}
+		return "", unsupportedTypeError(i, "string")
+	case reflect.Complex64, reflect.Complex128:
+		return "", unsupportedTypeError(i, "string")
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return ToString(i), nil
+		}
+		if rv.Kind() != reflect.Array && rv.Kind() != reflect.Struct && rv.IsNil() {
+			return "", nil
+		}
+		return ToJSONStringE(rv.Interface())
+	}
+	return ToString(i), nil
+}
+
+// isNilPointer reports whether i holds a nil pointer.
+func isNilPointer(i any) bool {
+	v := reflect.ValueOf(i)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// jsonValue rebuilds maps with keys that json can't encode, such as the
+// map[interface{}]interface{} decoded from yaml, as map[string]any keyed
+// by ToString, so that they are still marshaled with sorted keys.
+func jsonValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonValue(v.Elem())
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[ToString(iter.Key().Interface())] = jsonValue(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		r := make([]any, v.Len())
+		for i := range r {
+			r[i] = jsonValue(v.Index(i))
+		}
+		return r
+	default:
+		if !v.CanInterface() {
+			return nil
+		}
+		return v.Interface()
+	}
+}
+
+// ToJSONString casts an any to its json string.
+// When type is clear, it is recommended to use standard library functions.
+func ToJSONString(i any) string {
+	v, _ := ToJSONStringE(i)
+	return v
+}
+
+// ToJSONStringE casts an any to its json string, the same one ToString
+// renders for composite values, but returns the marshaling error instead
+// of falling back to fmt.Sprint.
+// When type is clear, it is recommended to use standard library functions.
+func ToJSONStringE(i any) (string, error) {
+	jb, err := json.Marshal(i)
+	if err == nil {
+		return string(jb), nil
+	}
+	if b, e := json.Marshal(jsonValue(reflect.ValueOf(i))); e == nil {
+		return string(b), nil
+	}
+	return "", err
+}
+
+// ToStringOr casts an any to a string, it returns def when i is nil.
+func ToStringOr(i any, def string) string {
+	if i == nil {
+		return def
+	}
+	return ToString(i)
+}
+
+// ToStringWith casts an any to a string like ToString, but time.Time and
+// *time.Time values are formatted with the TimeFormat option, floats with
+// the FloatFormat option, durations as integer nanoseconds with the
+// DurationAsNanos option, and []byte values are base64 encoded with the
+// Base64Bytes option when given. The result is then cleaned up by the
+// NormalizeNewlines and TrimSpace options.
+func ToStringWith(i any, opts ...Option) string {
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	s := toStringWith(i, &arg)
+	if arg.NormalizeNewlines {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+		s = strings.ReplaceAll(s, "\r", "\n")
+	}
+	if arg.TrimSpace {
+		s = strings.TrimSpace(strings.TrimPrefix(s, "\uFEFF"))
+	}
+	return s
+}
+
+func toStringWith(i any, arg *OptionArg) string {
+	switch s := i.(type) {
+	case time.Time:
+		if arg.TimeFormat != "" {
+			return s.Format(arg.TimeFormat)
+		}
+	case *time.Time:
+		if s != nil && arg.TimeFormat != "" {
+			return s.Format(arg.TimeFormat)
+		}
+	case float32:
+		if arg.FloatFormat != 0 {
+			return strconv.FormatFloat(float64(s), arg.FloatFormat, arg.FloatPrecision, 32)
+		}
+	case float64:
+		if arg.FloatFormat != 0 {
+			return strconv.FormatFloat(s, arg.FloatFormat, arg.FloatPrecision, 64)
+		}
+	case *float32:
+		if s != nil && arg.FloatFormat != 0 {
+			return strconv.FormatFloat(float64(*s), arg.FloatFormat, arg.FloatPrecision, 32)
+		}
+	case *float64:
+		if s != nil && arg.FloatFormat != 0 {
+			return strconv.FormatFloat(*s, arg.FloatFormat, arg.FloatPrecision, 64)
+		}
+	case time.Duration:
+		if arg.DurationAsNanos {
+			return strconv.FormatInt(int64(s), 10)
+		}
+	case *time.Duration:
+		if s != nil && arg.DurationAsNanos {
+			return strconv.FormatInt(int64(*s), 10)
+		}
+	case []byte:
+		if arg.Base64Bytes {
+			return base64.StdEncoding.EncodeToString(s)
+		}
+	case *[]byte:
+		if arg.Base64Bytes {
+			if s == nil {
+				return ""
+			}
+			return base64.StdEncoding.EncodeToString(*s)
+		}
+	}
+	return ToString(i)
+}
@@ -19,6 +19,7 @@ package cast_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"github.com/lvan100/cast"
 	"github.com/lvan100/cast/internal/assert"
 	"html/template"
@@ -108,6 +109,11 @@ func TestToString(t *testing.T) {
 	var time2 *time.Time
 	assert.Equal(t, cast.ToString(time2), "")
 
+	assert.Equal(t, cast.ToString(time.Hour), "1h0m0s")
+	assert.Equal(t, cast.ToString(1500*time.Millisecond), "1.5s")
+	var d1 *time.Duration
+	assert.Equal(t, cast.ToString(d1), "")
+
 	type Stu struct {
 		Name string
 	}
@@ -119,4 +125,194 @@ func TestToString(t *testing.T) {
 	var f = func(a, b int) {}
 	_ = cast.ToString(&f)
 
+	assert.Equal(t, cast.ToString(map[string]int{"b": 2, "a": 1}), `{"a":1,"b":2}`)
+	assert.Equal(t, cast.ToString(map[int]string{10: "b", 2: "a"}), `{"10":"b","2":"a"}`)
+	assert.Equal(t, cast.ToString(map[interface{}]interface{}{
+		"b": []interface{}{map[interface{}]interface{}{true: 1.5}},
+		"a": nil,
+	}), `{"a":null,"b":[{"true":1.5}]}`)
+	assert.Equal(t, cast.ToString(map[float64]int{1.5: 1, 0.5: 2}), `{"0.5":2,"1.5":1}`)
+
+}
+
+func TestToStringWith(t *testing.T) {
+
+	t1 := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, cast.ToStringWith(t1, cast.TimeFormat(time.RFC3339)), "2023-01-02T15:04:05Z")
+	assert.Equal(t, cast.ToStringWith(&t1, cast.TimeFormat(time.RFC3339)), "2023-01-02T15:04:05Z")
+	assert.Equal(t, cast.ToStringWith(t1), "2023-01-02 15:04:05 +0000 UTC")
+
+	var t2 *time.Time
+	assert.Equal(t, cast.ToStringWith(t2, cast.TimeFormat(time.RFC3339)), "")
+
+	assert.Equal(t, cast.ToStringWith(3, cast.TimeFormat(time.RFC3339)), "3")
+
+	assert.Equal(t, cast.ToStringWith(1e21), "1000000000000000000000")
+	assert.Equal(t, cast.ToStringWith(1e21, cast.FloatFormat('g', 6)), "1e+21")
+	assert.Equal(t, cast.ToStringWith(1.0/3, cast.FloatFormat('f', 2)), "0.33")
+	assert.Equal(t, cast.ToStringWith(float32(2.5), cast.FloatFormat('e', 1)), "2.5e+00")
+	assert.Equal(t, cast.ToStringWith(cast.Float64Ptr(0.000123), cast.FloatFormat('g', 2)), "0.00012")
+	assert.Equal(t, cast.ToStringWith(cast.Float32Ptr(1.5), cast.FloatFormat('f', 3)), "1.500")
+	assert.Equal(t, cast.ToStringWith(3, cast.FloatFormat('f', 2)), "3")
+
+	b := []byte{0xff, 0x00, 'a'}
+	assert.Equal(t, cast.ToStringWith(b), string(b))
+	assert.Equal(t, cast.ToStringWith(b, cast.Base64Bytes()), "/wBh")
+	assert.Equal(t, cast.ToStringWith(&b, cast.Base64Bytes()), "/wBh")
+	assert.Equal(t, cast.ToStringWith([]byte(nil), cast.Base64Bytes()), "")
+	assert.Equal(t, cast.ToStringWith((*[]byte)(nil), cast.Base64Bytes()), "")
+	assert.Equal(t, cast.ToStringWith("abc", cast.Base64Bytes()), "abc")
+
+	d := 1500 * time.Millisecond
+	assert.Equal(t, cast.ToStringWith(d), "1.5s")
+	assert.Equal(t, cast.ToStringWith(d, cast.DurationAsNanos()), "1500000000")
+	assert.Equal(t, cast.ToStringWith(&d, cast.DurationAsNanos()), "1500000000")
+	assert.Equal(t, cast.ToStringWith((*time.Duration)(nil), cast.DurationAsNanos()), "")
+
+	s, err := cast.To[string](t1, cast.TimeFormat("2006-01-02"))
+	assert.Nil(t, err)
+	assert.Equal(t, s, "2023-01-02")
+
+	assert.Equal(t, cast.ToString(" abc\n"), " abc\n")
+	assert.Equal(t, cast.ToStringWith(" abc\r\n", cast.TrimSpace()), "abc")
+	assert.Equal(t, cast.ToStringWith("\uFEFF abc\n", cast.TrimSpace()), "abc")
+	assert.Equal(t, cast.ToStringWith([]byte("\tabc "), cast.TrimSpace()), "abc")
+	assert.Equal(t, cast.ToStringWith("a\r\nb\rc\n", cast.NormalizeNewlines()), "a\nb\nc\n")
+	assert.Equal(t, cast.ToStringWith("a\r\nb\r\n", cast.NormalizeNewlines(), cast.TrimSpace()), "a\nb")
+
+	s, err = cast.To[string](" abc ", cast.TrimSpace())
+	assert.Nil(t, err)
+	assert.Equal(t, s, "abc")
+}
+
+func TestToStringOr(t *testing.T) {
+	assert.Equal(t, cast.ToStringOr(nil, "def"), "def")
+	assert.Equal(t, cast.ToStringOr("", "def"), "")
+	assert.Equal(t, cast.ToStringOr(3, "def"), "3")
+}
+
+type valueStringer struct{ Name string }
+
+func (s valueStringer) String() string { return "value:" + s.Name }
+
+type ptrStringer struct{ Name string }
+
+func (s *ptrStringer) String() string { return "ptr:" + s.Name }
+
+type ptrError struct{ Code int }
+
+func (e *ptrError) Error() string { return "code " + strconv.Itoa(e.Code) }
+
+func TestToStringStringer(t *testing.T) {
+	assert.Equal(t, cast.ToString(valueStringer{Name: "a"}), "value:a")
+	assert.Equal(t, cast.ToString(&valueStringer{Name: "a"}), "value:a")
+	assert.Equal(t, cast.ToString(ptrStringer{Name: "a"}), "ptr:a")
+	assert.Equal(t, cast.ToString(&ptrStringer{Name: "a"}), "ptr:a")
+	assert.Equal(t, cast.ToString(ptrError{Code: 3}), "code 3")
+	assert.Equal(t, cast.ToString(&ptrError{Code: 3}), "code 3")
+	assert.Equal(t, cast.ToString([]ptrStringer{{Name: "a"}}), `[{"Name":"a"}]`)
+}
+
+func TestToJSONString(t *testing.T) {
+
+	assert.Equal(t, cast.ToJSONString(nil), "null")
+	assert.Equal(t, cast.ToJSONString("a"), `"a"`)
+	assert.Equal(t, cast.ToJSONString([]int{2}), "[2]")
+	assert.Equal(t, cast.ToJSONString(map[string]int{"b": 2, "a": 1}), cast.ToString(map[string]int{"b": 2, "a": 1}))
+	assert.Equal(t, cast.ToJSONString(map[any]any{1: "a"}), `{"1":"a"}`)
+
+	_, err := cast.ToJSONStringE(make(chan int))
+	assert.Error(t, err, "json: unsupported type: chan int")
+
+	_, err = cast.ToJSONStringE(map[string]any{"f": func() {}})
+	assert.Error(t, err, "json: unsupported type: func\\(\\)")
+}
+
+func TestToStringE(t *testing.T) {
+
+	s, err := cast.ToStringE(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, s, "")
+
+	s, err = cast.ToStringE(cast.IntPtr(3))
+	assert.Nil(t, err)
+	assert.Equal(t, s, "3")
+
+	s, err = cast.ToStringE(map[string]int{"b": 2, "a": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, s, `{"a":1,"b":2}`)
+
+	s, err = cast.ToStringE(map[interface{}]interface{}{1: "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, s, `{"1":"a"}`)
+
+	s, err = cast.ToStringE([]int(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, s, "")
+
+	s, err = cast.ToStringE([]byte("abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, s, "abc")
+
+	s, err = cast.ToStringE(&ptrStringer{Name: "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, s, "ptr:a")
+
+	s, err = cast.ToStringE(ptrError{Code: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, s, "code 3")
+
+	s, err = cast.ToStringE(time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, s, "1s")
+
+	_, err = cast.ToStringE(make(chan int))
+	assert.Error(t, err, "unable to cast type \\(chan int\\) to string")
+
+	_, err = cast.ToStringE(func() {})
+	assert.Error(t, err, "unable to cast type \\(func\\(\\)\\) to string")
+
+	_, err = cast.ToStringE(complex(1, 2))
+	assert.Error(t, err, "unable to cast type \\(complex128\\) to string")
+
+	_, err = cast.ToStringE(map[string]any{"f": func() {}})
+	assert.Error(t, err, "json: unsupported type: func\\(\\)")
+
+	assert.Equal(t, cast.ToString(complex(1, 2)), "(1+2i)")
+}
+
+func TestToStringNil(t *testing.T) {
+
+	type Stu struct{ Name string }
+
+	var (
+		ip *int
+		pp = &ip
+		e  error
+		st fmt.Stringer = (*ptrStringer)(nil)
+	)
+	for _, v := range []any{
+		(*int)(nil),
+		(*string)(nil),
+		(*Stu)(nil),
+		map[string]int(nil),
+		[]int(nil),
+		[]byte(nil),
+		(func())(nil),
+		(chan int)(nil),
+		pp,
+		(*any)(nil),
+		e,
+		st,
+		(*bytes.Buffer)(nil),
+		(*valueStringer)(nil),
+		(*ptrError)(nil),
+		(*time.Duration)(nil),
+	} {
+		assert.Equal(t, cast.ToString(v), "")
+		assert.Equal(t, cast.ToStringWith(v), "")
+		s, err := cast.ToStringE(v)
+		assert.Nil(t, err)
+		assert.Equal(t, s, "")
+	}
 }
@@ -17,10 +17,28 @@
 package cast
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+var defaultTimeFormat atomic.Value
+
+func init() {
+	defaultTimeFormat.Store("2006-01-02 15:04:05 -0700")
+}
+
+// SetDefaultTimeFormat sets the layout used to parse time strings when
+// neither TimeFormat nor TimeFormats is given, "2006-01-02 15:04:05 -0700"
+// by default. It is safe to call at any time, but it is meant to be called
+// once at startup, a change is seen by the casts that start after it.
+func SetDefaultTimeFormat(layout string) {
+	defaultTimeFormat.Store(layout)
+}
+
 // ToTime casts an any to a time.Time.
 // When type is clear, it is recommended to use standard library functions.
 func ToTime(i any, opts ...Option) time.Time {
@@ -28,6 +46,19 @@ func ToTime(i any, opts ...Option) time.Time {
 	return v
 }
 
+// ToTimeOr casts an any to a time.Time, it returns def when i is nil or
+// the cast fails.
+func ToTimeOr(i any, def time.Time, opts ...Option) time.Time {
+	if i == nil {
+		return def
+	}
+	v, err := ToTimeE(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 // ToTimeE casts an any to a time.Time.
 // When type is clear, it is recommended to use standard library functions.
 func ToTimeE(i any, opts ...Option) (time.Time, error) {
@@ -86,36 +117,144 @@ func ToTimeE(i any, opts ...Option) (time.Time, error) {
 		return parseFormatTime(v, opts...)
 	case *string:
 		return parseFormatTime(*v, opts...)
+	case time.Duration:
+		return durationTime(v, opts...), nil
+	case *time.Duration:
+		if v == nil {
+			return time.Time{}, unsupportedTypeError(i, "Time")
+		}
+		return durationTime(*v, opts...), nil
 	case time.Time:
 		return v, nil
 	case *time.Time:
 		return *v, nil
+	case []byte:
+		return parseFormatTime(string(v), opts...)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return parseTimestamp(n, opts...), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, parseError(string(v), "Time", err)
+		}
+		return parseTimestamp(f, opts...), nil
 	default:
-		return time.Time{}, fmt.Errorf("unable to cast type (%T) to Time", i)
+		return time.Time{}, unsupportedTypeError(i, "Time")
 	}
 }
 
 func parseTimestamp[T int64 | float64](v T, opts ...Option) time.Time {
-	arg := OptionArg{
-		TimeFormat: "ns",
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	if arg.TimeFormat == "" && arg.AutoTimeUnit {
+		arg.TimeFormat = timestampUnit(float64(v))
+	}
+	unitN, ok := unitMap[arg.TimeFormat]
+	if !ok {
+		unitN = int64(time.Nanosecond)
+	}
+	var t time.Time
+	switch x := any(v).(type) {
+	case int64:
+		// Integers are computed exactly, a float64 only has 52 bits of
+		// mantissa and would drop the tail of a nanosecond timestamp.
+		if second := int64(time.Second); unitN >= second {
+			t = time.Unix(x*(unitN/second), 0)
+		} else {
+			n := second / unitN
+			t = time.Unix(x/n, x%n*unitN)
+		}
+	case float64:
+		i := int64(x * float64(unitN))
+		t = time.Unix(i/int64(time.Second), i%int64(time.Second))
+	}
+	if arg.Location != nil {
+		t = t.In(arg.Location)
 	}
+	return t
+}
+
+// timestampUnit guesses the unit of an epoch timestamp by its magnitude,
+// values below 1e11 (about 10 digits) are seconds, below 1e14 (about 13
+// digits) milliseconds, below 1e17 (about 16 digits) microseconds, and
+// anything larger nanoseconds.
+func timestampUnit(v float64) string {
+	switch v = math.Abs(v); {
+	case v < 1e11:
+		return "s"
+	case v < 1e14:
+		return "ms"
+	case v < 1e17:
+		return "μs"
+	default:
+		return "ns"
+	}
+}
+
+// durationTime returns the time that is d after the Unix epoch.
+func durationTime(d time.Duration, opts ...Option) time.Time {
+	t := time.Unix(int64(d/time.Second), int64(d%time.Second))
+	if len(opts) == 0 {
+		return t
+	}
+	var arg OptionArg
 	for _, opt := range opts {
 		opt(&arg)
 	}
-	unitN, _ := unitMap[arg.TimeFormat]
-	i := int64(float64(v) * float64(unitN))
-	return time.Unix(i/int64(time.Second), i%int64(time.Second))
+	if arg.Location != nil {
+		t = t.In(arg.Location)
+	}
+	return t
 }
 
 func parseFormatTime(v string, opts ...Option) (time.Time, error) {
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
 	if d, err := time.ParseDuration(v); err == nil {
-		return time.Unix(int64(d/time.Second), int64(d%time.Second)), nil
+		return durationTime(d, opts...), nil
 	}
-	arg := OptionArg{
-		TimeFormat: "2006-01-02 15:04:05 -0700",
+	parse := time.Parse
+	if arg.Location != nil {
+		parse = func(layout, value string) (time.Time, error) {
+			return time.ParseInLocation(layout, value, arg.Location)
+		}
 	}
-	for _, opt := range opts {
-		opt(&arg)
+	layouts := arg.TimeFormats
+	if arg.TimeFormat != "" {
+		layouts = append([]string{arg.TimeFormat}, layouts...)
+	}
+	var err error
+	switch len(layouts) {
+	case 0:
+		var t time.Time
+		if t, err = parse(defaultTimeFormat.Load().(string), v); err == nil {
+			return t, nil
+		}
+	case 1:
+		var t time.Time
+		if t, err = parse(layouts[0], v); err == nil {
+			return t, nil
+		}
+	default:
+		for _, layout := range layouts {
+			if t, e := parse(layout, v); e == nil {
+				return t, nil
+			}
+		}
+		err = fmt.Errorf("unable to parse %q as time with layouts %q", v, layouts)
+	}
+	// A bare number is an epoch in the unit of TimeFormat, like a number
+	// that isn't a string.
+	if i, e := strconv.ParseInt(v, 10, 64); e == nil {
+		return parseTimestamp(i, opts...), nil
+	}
+	if f, e := strconv.ParseFloat(v, 64); e == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return parseTimestamp(f, opts...), nil
 	}
-	return time.Parse(arg.TimeFormat, v)
+	return time.Time{}, parseError(v, "Time", err)
 }
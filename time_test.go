@@ -17,6 +17,7 @@
 package cast_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -122,3 +123,163 @@ func TestToTime(t *testing.T) {
 	_, err = cast.ToTimeE("abc")
 	assert.Error(t, err, "cannot parse \"abc\" as \"2006\"")
 }
+
+func TestToTimeFormats(t *testing.T) {
+
+	expect := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	opt := cast.TimeFormats(time.RFC3339, time.RFC1123, "2006-01-02")
+
+	got, err := cast.ToTimeE("2023-01-02T15:04:05Z", opt)
+	assert.Nil(t, err)
+	assert.True(t, got.Equal(expect))
+
+	got, err = cast.ToTimeE("Mon, 02 Jan 2023 15:04:05 UTC", opt)
+	assert.Nil(t, err)
+	assert.True(t, got.Equal(expect))
+
+	got, err = cast.ToTimeE("2023-01-02", opt)
+	assert.Nil(t, err)
+	assert.True(t, got.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	got, err = cast.ToTimeE("2023-01-02 15:04:05", opt, cast.TimeFormat("2006-01-02 15:04:05"))
+	assert.Nil(t, err)
+	assert.True(t, got.Equal(expect))
+
+	_, err = cast.ToTimeE("abc", opt)
+	assert.Error(t, err, "unable to parse \"abc\" as time with layouts \\[\"2006-01-02T15:04:05Z07:00\" \"Mon, 02 Jan 2006 15:04:05 MST\" \"2006-01-02\"\\]")
+}
+
+func TestToTimeLocation(t *testing.T) {
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cast.ToTimeE("2023-01-02 15:04:05", cast.TimeFormat("2006-01-02 15:04:05"), cast.Location(location))
+	assert.Nil(t, err)
+	assert.Equal(t, got, time.Date(2023, 1, 2, 15, 4, 5, 0, location))
+
+	got, err = cast.ToTimeE("2023-01-02 15:04:05", cast.TimeFormat("2006-01-02 15:04:05"))
+	assert.Nil(t, err)
+	assert.Equal(t, got, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+
+	got = cast.ToTime(int64(1672689845), cast.TimeFormat("s"), cast.Location(location))
+	assert.Equal(t, got.Location(), location)
+	assert.Equal(t, got.Hour(), 15)
+
+	got = cast.ToTime("3ns", cast.Location(location))
+	assert.Equal(t, got, time.Unix(0, 3).In(location))
+}
+
+func TestToTimePrecision(t *testing.T) {
+
+	got := cast.ToTime(int64(1700000000123456789))
+	assert.Equal(t, got.Unix(), int64(1700000000))
+	assert.Equal(t, got.Nanosecond(), 123456789)
+	assert.Equal(t, got.UnixNano(), int64(1700000000123456789))
+
+	got = cast.ToTime(int64(1700000000123456), cast.TimeFormat("μs"))
+	assert.Equal(t, got.UnixNano(), int64(1700000000123456000))
+
+	got = cast.ToTime(int64(-1500), cast.TimeFormat("ms"))
+	assert.Equal(t, got.UnixNano(), int64(-1500000000))
+
+	got = cast.ToTime(int64(2), cast.TimeFormat("h"))
+	assert.Equal(t, got, time.Unix(7200, 0))
+
+	got = cast.ToTime(1.5, cast.TimeFormat("s"))
+	assert.Equal(t, got, time.Unix(1, 500000000))
+
+	got = cast.ToTime(3, cast.TimeFormat("2006-01-02"))
+	assert.Equal(t, got, time.Unix(0, 3))
+}
+
+func TestToTimeAutoTimeUnit(t *testing.T) {
+
+	expect := time.Unix(1700000000, 0)
+	assert.Equal(t, cast.ToTime(int64(1700000000), cast.AutoTimeUnit()), expect)
+	assert.Equal(t, cast.ToTime(int64(1700000000000), cast.AutoTimeUnit()), expect)
+	assert.Equal(t, cast.ToTime(int64(1700000000000000), cast.AutoTimeUnit()), expect)
+	assert.Equal(t, cast.ToTime(int64(1700000000000000000), cast.AutoTimeUnit()), expect)
+	assert.Equal(t, cast.ToTime(1700000000.5, cast.AutoTimeUnit()), time.Unix(1700000000, 500000000))
+
+	got := cast.ToTime(int64(1700000000), cast.AutoTimeUnit(), cast.TimeFormat("ms"))
+	assert.Equal(t, got, time.Unix(1700000, 0))
+}
+
+func TestToTimeOr(t *testing.T) {
+	def := time.Unix(7, 0)
+	assert.Equal(t, cast.ToTimeOr(nil, def), def)
+	assert.Equal(t, cast.ToTimeOr(true, def), def)
+	assert.Equal(t, cast.ToTimeOr(3, def, cast.TimeFormat("s")), time.Unix(3, 0))
+}
+
+func TestToTimeDuration(t *testing.T) {
+
+	assert.Equal(t, cast.ToTime(time.Hour), time.Unix(3600, 0))
+	assert.Equal(t, cast.ToTime(time.Hour), cast.ToTime("1h"))
+	assert.Equal(t, cast.ToTime(1500*time.Millisecond), time.Unix(1, int64(500*time.Millisecond)))
+
+	d := -time.Second
+	assert.Equal(t, cast.ToTime(&d), time.Unix(-1, 0))
+
+	_, err := cast.ToTimeE((*time.Duration)(nil))
+	assert.Error(t, err, "unable to cast type \\(\\*time.Duration\\) to Time")
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	v := cast.ToTime(time.Hour, cast.Location(loc))
+	assert.Equal(t, v.Location(), loc)
+	assert.True(t, v.Equal(time.Unix(3600, 0)))
+}
+
+func TestToTimeNumericString(t *testing.T) {
+
+	assert.Equal(t, cast.ToTime("1700000000", cast.TimeFormat("s")), time.Unix(1700000000, 0))
+	assert.Equal(t, cast.ToTime(cast.StringPtr("1700000000123"), cast.TimeFormat("ms")), time.UnixMilli(1700000000123))
+	assert.Equal(t, cast.ToTime("1.5", cast.TimeFormat("s")), time.Unix(1, int64(500*time.Millisecond)))
+	assert.Equal(t, cast.ToTime("1700000000", cast.AutoTimeUnit()), cast.ToTime(1700000000, cast.AutoTimeUnit()))
+	assert.Equal(t, cast.ToTime("1700000000"), cast.ToTime(int64(1700000000)))
+
+	_, err := cast.ToTimeE("NaN")
+	assert.Error(t, err, "parsing time \"NaN\"")
+
+	_, err = cast.ToTimeE("abc", cast.TimeFormats(time.RFC3339, time.DateOnly))
+	assert.Error(t, err, "unable to parse \"abc\" as time with layouts")
+}
+
+func TestSetDefaultTimeFormat(t *testing.T) {
+
+	_, err := cast.ToTimeE("2023-01-02T15:04:05Z")
+	assert.Error(t, err, "cannot parse")
+
+	cast.SetDefaultTimeFormat(time.RFC3339)
+	defer cast.SetDefaultTimeFormat("2006-01-02 15:04:05 -0700")
+
+	v, err := cast.ToTimeE("2023-01-02T15:04:05Z")
+	assert.Nil(t, err)
+	assert.True(t, v.Equal(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)))
+
+	v, err = cast.ToTimeE("2023-01-02", cast.TimeFormat(time.DateOnly))
+	assert.Nil(t, err)
+	assert.True(t, v.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	_, err = cast.ToTimeE("2023-01-02 15:04:05 +0000")
+	assert.Error(t, err, "cannot parse")
+}
+
+func TestToTimeBytesAndNumber(t *testing.T) {
+
+	v, err := cast.ToTimeE([]byte("2023-01-02 15:04:05"), cast.TimeFormat(time.DateTime))
+	assert.Nil(t, err)
+	assert.True(t, v.Equal(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)))
+
+	_, err = cast.ToTimeE([]byte("abc"), cast.TimeFormat(time.DateTime))
+	assert.Error(t, err, "parsing time \"abc\"")
+
+	assert.Equal(t, cast.ToTime(json.Number("1700000000"), cast.TimeFormat("s")), time.Unix(1700000000, 0))
+	assert.Equal(t, cast.ToTime(json.Number("1.5"), cast.TimeFormat("s")), time.Unix(1, int64(500*time.Millisecond)))
+
+	_, err = cast.ToTimeE(json.Number("abc"))
+	assert.Error(t, err, "strconv.ParseFloat: parsing \"abc\": invalid syntax")
+}
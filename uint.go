@@ -17,71 +17,155 @@
 package cast
 
 import (
-	"fmt"
+	"errors"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ToUint casts an any to an uint.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint(i any) uint {
-	v, _ := ToUint64E(i)
-	return uint(v)
+func ToUint(i any, opts ...Option) uint {
+	v, _ := ToUintE(i, opts...)
+	return v
+}
+
+// ToUintOr casts an any to an uint, it returns def when i is nil or the
+// cast fails.
+func ToUintOr(i any, def uint, opts ...Option) uint {
+	if i == nil {
+		return def
+	}
+	v, err := ToUintE(i, opts...)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// ToUintE casts an any to an uint.
+// When type is clear, it is recommended to use standard library functions.
+func ToUintE(i any, opts ...Option) (uint, error) {
+	v, err := ToUint64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "uint")
+	}
+	v, err = narrowUint(v, 0, opts)
+	return uint(v), err
 }
 
 // ToUint8 casts an any to an uint8.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint8(i any) uint8 {
-	v, _ := ToUint64E(i)
-	return uint8(v)
+func ToUint8(i any, opts ...Option) uint8 {
+	v, _ := ToUint8E(i, opts...)
+	return v
+}
+
+// ToUint8E casts an any to an uint8.
+// When type is clear, it is recommended to use standard library functions.
+func ToUint8E(i any, opts ...Option) (uint8, error) {
+	v, err := ToUint64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "uint8")
+	}
+	v, err = narrowUint(v, 8, opts)
+	return uint8(v), err
 }
 
 // ToUint16 casts an any to an uint16.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint16(i any) uint16 {
-	v, _ := ToUint64E(i)
-	return uint16(v)
+func ToUint16(i any, opts ...Option) uint16 {
+	v, _ := ToUint16E(i, opts...)
+	return v
+}
+
+// ToUint16E casts an any to an uint16.
+// When type is clear, it is recommended to use standard library functions.
+func ToUint16E(i any, opts ...Option) (uint16, error) {
+	v, err := ToUint64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "uint16")
+	}
+	v, err = narrowUint(v, 16, opts)
+	return uint16(v), err
 }
 
 // ToUint32 casts an any to an uint32.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint32(i any) uint32 {
-	v, _ := ToUint64E(i)
-	return uint32(v)
+func ToUint32(i any, opts ...Option) uint32 {
+	v, _ := ToUint32E(i, opts...)
+	return v
+}
+
+// ToUint32E casts an any to an uint32.
+// When type is clear, it is recommended to use standard library functions.
+func ToUint32E(i any, opts ...Option) (uint32, error) {
+	v, err := ToUint64E(i, opts...)
+	if err != nil {
+		return 0, retarget(err, "uint32")
+	}
+	v, err = narrowUint(v, 32, opts)
+	return uint32(v), err
 }
 
 // ToUint64 casts an any to an uint64.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint64(i any) uint64 {
-	v, _ := ToUint64E(i)
+func ToUint64(i any, opts ...Option) uint64 {
+	v, _ := ToUint64E(i, opts...)
+	return v
+}
+
+// ToUint64Or casts an any to an uint64, it returns def when i is nil or
+// the cast fails.
+func ToUint64Or(i any, def uint64, opts ...Option) uint64 {
+	if i == nil {
+		return def
+	}
+	v, err := ToUint64E(i, opts...)
+	if err != nil {
+		return def
+	}
 	return v
 }
 
-// ToUint64E casts an any to an uint64.
+// ToUint64E casts an any to an uint64. A time.Duration is its count of
+// nanoseconds, and a time.Time is its Unix time in nanoseconds.
 // When type is clear, it is recommended to use standard library functions.
-func ToUint64E(i any) (uint64, error) {
+func ToUint64E(i any, opts ...Option) (uint64, error) {
+	v, err := toUint64(i, opts...)
+	if err != nil && saturate(opts) {
+		if e, ok := err.(*ConvertError); ok && e.Reason == ReasonNegative {
+			return 0, nil
+		}
+	}
+	return v, err
+}
+
+func toUint64(i any, opts ...Option) (uint64, error) {
 	switch s := i.(type) {
 	case nil:
 		return 0, nil
 	case int:
-		return uint64(s), nil
+		return intToUint64(int64(s))
 	case int8:
-		return uint64(s), nil
+		return intToUint64(int64(s))
 	case int16:
-		return uint64(s), nil
+		return intToUint64(int64(s))
 	case int32:
-		return uint64(s), nil
+		return intToUint64(int64(s))
 	case int64:
-		return uint64(s), nil
+		return intToUint64(int64(s))
 	case *int:
-		return uint64(*s), nil
+		return intToUint64(int64(*s))
 	case *int8:
-		return uint64(*s), nil
+		return intToUint64(int64(*s))
 	case *int16:
-		return uint64(*s), nil
+		return intToUint64(int64(*s))
 	case *int32:
-		return uint64(*s), nil
+		return intToUint64(int64(*s))
 	case *int64:
-		return uint64(*s), nil
+		return intToUint64(int64(*s))
 	case uint:
 		return uint64(s), nil
 	case uint8:
@@ -102,18 +186,22 @@ func ToUint64E(i any) (uint64, error) {
 		return uint64(*s), nil
 	case *uint64:
 		return *s, nil
-	case float32:
+	case uintptr:
 		return uint64(s), nil
+	case *uintptr:
+		return uint64(*s), nil
+	case float32:
+		return floatToUint64(float64(s), opts...)
 	case float64:
-		return uint64(s), nil
+		return floatToUint64(s, opts...)
 	case *float32:
-		return uint64(*s), nil
+		return floatToUint64(float64(*s), opts...)
 	case *float64:
-		return uint64(*s), nil
+		return floatToUint64(*s, opts...)
 	case string:
-		return strconv.ParseUint(s, 0, 0)
+		return parseUint64(s, opts...)
 	case *string:
-		return strconv.ParseUint(*s, 0, 0)
+		return parseUint64(*s, opts...)
 	case bool:
 		if s {
 			return 1, nil
@@ -124,6 +212,101 @@ func ToUint64E(i any) (uint64, error) {
 			return 1, nil
 		}
 		return 0, nil
+	case []byte:
+		return parseUint64(string(s), opts...)
+	case time.Duration:
+		return intToUint64(int64(s))
+	case *time.Duration:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "uint64")
+		}
+		return intToUint64(int64(*s))
+	case time.Time:
+		return intToUint64(s.UnixNano())
+	case *time.Time:
+		if s == nil {
+			return 0, unsupportedTypeError(i, "uint64")
+		}
+		return intToUint64(s.UnixNano())
+	}
+	if v, ok := basicOf(i); ok {
+		return toUint64(v, opts...)
+	}
+	return 0, unsupportedTypeError(i, "uint64")
+}
+
+// narrowUint checks that v fits in an unsigned integer of the bit size, 0
+// means uint. With the Saturate option v is clamped to the maximum instead.
+func narrowUint(v uint64, bitSize int, opts []Option) (uint64, error) {
+	name := "uint"
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	} else {
+		name += strconv.Itoa(bitSize)
+	}
+	maxV := uint64(1)<<bitSize - 1
+	if v <= maxV {
+		return v, nil
+	}
+	if !saturate(opts) {
+		return 0, overflowError(v, name)
+	}
+	return maxV, nil
+}
+
+func intToUint64(v int64) (uint64, error) {
+	if v < 0 {
+		return 0, negativeError(v, "uint64")
+	}
+	return uint64(v), nil
+}
+
+func floatToUint64(v float64, opts ...Option) (uint64, error) {
+	v, err := integral(v, "uint64", opts...)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, negativeError(v, "uint64")
+	}
+	if math.IsNaN(v) || v >= math.MaxUint64 {
+		if !math.IsNaN(v) && saturate(opts) {
+			return math.MaxUint64, nil
+		}
+		return 0, overflowError(v, "uint64")
+	}
+	return uint64(v), nil
+}
+
+func parseUint64(s string, opts ...Option) (uint64, error) {
+	var arg OptionArg
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" && arg.EmptyAsZero {
+		return 0, nil
+	}
+	if arg.StripUnderscores {
+		s = strings.ReplaceAll(s, "_", "")
+	}
+	s = delocalize(s, &arg)
+	if strings.HasPrefix(s, "-") {
+		v, err := strconv.ParseInt(s, arg.Base, 0)
+		if err == nil && v < 0 {
+			return 0, negativeError(v, "uint64")
+		}
+		if errors.Is(err, strconv.ErrRange) && v < 0 {
+			return 0, negativeError(s, "uint64")
+		}
+	}
+	v, err := strconv.ParseUint(s, arg.Base, 0)
+	if err != nil {
+		// ParseUint gives the maximum for an out of range number.
+		if arg.Saturate && errors.Is(err, strconv.ErrRange) {
+			return v, nil
+		}
+		return v, parseError(s, "uint64", err)
 	}
-	return 0, fmt.Errorf("unable to cast type (%T) to uint64", i)
+	return v, nil
 }
@@ -17,7 +17,9 @@
 package cast_test
 
 import (
+	"bytes"
 	"errors"
+	"math"
 	"testing"
 
 	"github.com/lvan100/cast"
@@ -63,9 +65,105 @@ func TestToUint(t *testing.T) {
 	assert.Equal(t, cast.ToUint64(cast.BoolPtr(true)), uint64(1))
 	assert.Equal(t, cast.ToUint64(cast.BoolPtr(false)), uint64(0))
 
+	assert.Equal(t, cast.ToUint64(" 42 "), uint64(42))
+	assert.Equal(t, cast.ToUint64(cast.StringPtr("42\n")), uint64(42))
+
+	type ID uint32
+	assert.Equal(t, cast.ToUint64([]byte("42")), uint64(42))
+	assert.Equal(t, cast.ToUint64(ID(42)), uint64(42))
+	assert.Equal(t, cast.ToUint64(bytes.NewBufferString("42")), uint64(42))
+
 	_, err := cast.ToUint64E("abc")
 	assert.Error(t, err, "strconv.ParseUint: parsing \"abc\": invalid syntax")
 
 	_, err = cast.ToUint64E(errors.New("abc"))
 	assert.Error(t, err, "unable to cast type \\(\\*errors\\.errorString\\) to uint64")
+
+	_, err = cast.ToUint64E(float64(1e20))
+	assert.Error(t, err, "value 1e\\+20 overflows uint64")
+
+	assert.Equal(t, cast.ToUint8(300), uint8(0))
+
+	_, err = cast.ToUint8E(300)
+	assert.Error(t, err, "value 300 overflows uint8")
+
+	_, err = cast.ToUint16E("70000")
+	assert.Error(t, err, "value 70000 overflows uint16")
+
+	_, err = cast.ToUint32E(uint64(math.MaxUint32 + 1))
+	assert.Error(t, err, "value 4294967296 overflows uint32")
+
+	_, err = cast.To[uint8](256)
+	assert.Error(t, err, "value 256 overflows uint8")
+
+	_, err = cast.ToUint64E(-5)
+	assert.Error(t, err, "unable to cast negative value -5 to uint64")
+
+	_, err = cast.ToUint64E(cast.IntPtr(-5))
+	assert.Error(t, err, "unable to cast negative value -5 to uint64")
+
+	_, err = cast.ToUint64E("-5")
+	assert.Error(t, err, "unable to cast negative value -5 to uint64")
+
+	_, err = cast.ToUint64E(float64(-1))
+	assert.Error(t, err, "unable to cast negative value -1 to uint64")
+
+	_, err = cast.ToUint16E(int8(-1))
+	assert.Error(t, err, "unable to cast negative value -1 to uint16")
+}
+
+func TestToUintBase(t *testing.T) {
+
+	assert.Equal(t, cast.ToUint64("0x1F"), uint64(31))
+	assert.Equal(t, cast.ToUint64("010"), uint64(8))
+
+	assert.Equal(t, cast.ToUint64("010", cast.Base(10)), uint64(10))
+	assert.Equal(t, cast.ToUint16("ff", cast.Base(16)), uint16(255))
+	assert.Equal(t, cast.ToUint64("1_000", cast.Base(10), cast.StripUnderscores()), uint64(1000))
+
+	_, err := cast.ToUint64E("0x1F", cast.Base(10))
+	assert.Error(t, err, "strconv.ParseUint: parsing \"0x1F\": invalid syntax")
+
+	_, err = cast.ToUint64E("-010", cast.Base(10))
+	assert.Error(t, err, "unable to cast negative value -10 to uint64")
+}
+
+func TestToUintOr(t *testing.T) {
+	assert.Equal(t, cast.ToUintOr(nil, 7), uint(7))
+	assert.Equal(t, cast.ToUintOr(-1, 7), uint(7))
+	assert.Equal(t, cast.ToUintOr("3", 7), uint(3))
+	assert.Equal(t, cast.ToUint64Or(nil, 7), uint64(7))
+	assert.Equal(t, cast.ToUint64Or("abc", 7), uint64(7))
+	assert.Equal(t, cast.ToUint64Or("0", 7), uint64(0))
+}
+
+func TestToUintNarrowing(t *testing.T) {
+	assert.Equal(t, cast.ToUint("42"), uint(42))
+	assert.Equal(t, cast.ToUint8("42"), uint8(42))
+	assert.Equal(t, cast.ToUint16("42"), uint16(42))
+	assert.Equal(t, cast.ToUint32("42"), uint32(42))
+	assert.Equal(t, cast.ToUint64("42"), uint64(42))
+	assert.Equal(t, cast.ToFloat32("42"), float32(42))
+	assert.Equal(t, cast.ToFloat64("42"), float64(42))
+
+	// Values that wrap to non-zero values, 44, 4464 and 4, so a wrapped
+	// result can't pass for the zero of an overflow.
+	assert.Equal(t, cast.ToUint8(300), uint8(0))
+	assert.Equal(t, cast.ToUint16(70000), uint16(0))
+	assert.Equal(t, cast.ToUint32(uint64(math.MaxUint32+5)), uint32(0))
+	assert.Equal(t, cast.ToUint(-1), uint(0))
+
+	_, err := cast.ToUint8E(256)
+	assert.Error(t, err, "value 256 overflows uint8")
+
+	_, err = cast.ToUint8E(300)
+	assert.Error(t, err, "value 300 overflows uint8")
+
+	_, err = cast.ToUint16E(70000)
+	assert.Error(t, err, "value 70000 overflows uint16")
+
+	_, err = cast.ToUint32E(uint64(math.MaxUint32 + 5))
+	assert.Error(t, err, "value 4294967300 overflows uint32")
+	assert.Equal(t, cast.ToUint8(uint16(math.MaxUint8)), uint8(math.MaxUint8))
+	assert.Equal(t, cast.ToUint16("0x10", cast.Base(0)), uint16(16))
 }